@@ -0,0 +1,198 @@
+package auth
+
+import (
+	"admin_server/backend/internal/config"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Roles, from least to most privileged. Each role includes the ones before it.
+const (
+	RoleViewer    = "viewer"
+	RoleOperator  = "operator"
+	RoleRuleAdmin = "rule-admin"
+)
+
+var roleOrder = []string{RoleViewer, RoleOperator, RoleRuleAdmin}
+
+// identityKey is the gin context key holding the authenticated *Identity
+const identityKey = "auth.identity"
+
+var (
+	// ErrNoCredentials is returned when a request carries no token at all
+	ErrNoCredentials = errors.New("missing bearer token")
+	// ErrInvalidToken is returned when no authenticator accepts the token
+	ErrInvalidToken = errors.New("invalid token")
+)
+
+// Identity is the authenticated caller
+type Identity struct {
+	Subject string `json:"subject"`         // stable id recorded as author/actor
+	Email   string `json:"email,omitempty"` // JWT email claim, for display only
+	Role    string `json:"role"`
+	Method  string `json:"method"` // "token" or "jwt"
+}
+
+// Authenticator validates a bearer token
+type Authenticator interface {
+	Authenticate(token string) (*Identity, error)
+}
+
+// RoleRank returns the privilege level of role, or -1 when unknown
+func RoleRank(role string) int {
+	for i, r := range roleOrder {
+		if r == role {
+			return i
+		}
+	}
+	return -1
+}
+
+// HighestRole returns the most privileged known role in roles, or "" when none is known
+func HighestRole(roles []string) string {
+	best := ""
+	for _, role := range roles {
+		if RoleRank(role) > RoleRank(best) {
+			best = role
+		}
+	}
+	return best
+}
+
+// Chain tries each authenticator in order and returns the first identity
+type Chain []Authenticator
+
+func (c Chain) Authenticate(token string) (*Identity, error) {
+	var lastErr error = ErrInvalidToken
+	for _, a := range c {
+		identity, err := a.Authenticate(token)
+		if err == nil {
+			return identity, nil
+		}
+		lastErr = err
+	}
+	return nil, lastErr
+}
+
+// QueryToken moves a GET request's ?access_token= (EventSource cannot set headers)
+// into the Authorization header and drops it from the URL. Install it before the
+// request logger so the token never reaches the access log.
+func QueryToken() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Method != http.MethodGet {
+			c.Next()
+			return
+		}
+		query := c.Request.URL.Query()
+		token := query.Get("access_token")
+		if token == "" {
+			c.Next()
+			return
+		}
+		query.Del("access_token")
+		c.Request.URL.RawQuery = query.Encode()
+		if c.GetHeader("Authorization") == "" {
+			c.Request.Header.Set("Authorization", "Bearer "+token)
+		}
+		c.Next()
+	}
+}
+
+// Middleware authenticates the Authorization: Bearer header, which QueryToken fills
+// from ?access_token= for EventSource GET requests
+func Middleware(authenticator Authenticator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token := bearerToken(c.GetHeader("Authorization"))
+		if token == "" {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": ErrNoCredentials.Error()})
+			return
+		}
+
+		identity, err := authenticator.Authenticate(token)
+		if err != nil {
+			log.Printf("WARN: Authentication failed for %s %s: %v", c.Request.Method, c.Request.URL.Path, err)
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": ErrInvalidToken.Error()})
+			return
+		}
+
+		c.Set(identityKey, identity)
+		c.Next()
+	}
+}
+
+// RequireRole rejects callers whose role is below role
+func RequireRole(role string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		identity := IdentityFrom(c)
+		if identity == nil {
+			// 인증 비활성화 상태 (AUTH_ENABLED=false)
+			c.Next()
+			return
+		}
+		if RoleRank(identity.Role) < RoleRank(role) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "role " + role + " required"})
+			return
+		}
+		c.Next()
+	}
+}
+
+// IdentityFrom returns the authenticated caller, or nil when auth is disabled
+func IdentityFrom(c *gin.Context) *Identity {
+	value, ok := c.Get(identityKey)
+	if !ok {
+		return nil
+	}
+	identity, _ := value.(*Identity)
+	return identity
+}
+
+func bearerToken(header string) string {
+	scheme, token, ok := strings.Cut(header, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return ""
+	}
+	return strings.TrimSpace(token)
+}
+
+// NewFromConfig builds the authenticator chain from cfg. It returns nil when
+// AUTH_ENABLED=false, and an error when auth is enabled but nothing is configured.
+func NewFromConfig(cfg *config.Config) (Authenticator, error) {
+	if !cfg.AuthEnabled {
+		return nil, nil
+	}
+
+	var chain Chain
+	if len(cfg.AuthAPITokens) > 0 {
+		static, err := NewStaticTokenAuthenticator(cfg.AuthAPITokens)
+		if err != nil {
+			return nil, err
+		}
+		chain = append(chain, static)
+	}
+	if cfg.AuthJWKSURL != "" {
+		jwt, err := NewJWTAuthenticator(JWTConfig{
+			JWKSURL:      cfg.AuthJWKSURL,
+			Issuer:       cfg.AuthJWTIssuer,
+			Audience:     cfg.AuthJWTAudience,
+			RolesClaim:   cfg.AuthRolesClaim,
+			SubjectClaim: cfg.AuthSubjectClaim,
+			Refresh:      cfg.AuthJWKSRefresh,
+			ClockSkew:    time.Minute,
+		})
+		if err != nil {
+			return nil, err
+		}
+		chain = append(chain, jwt)
+	}
+
+	if len(chain) == 0 {
+		return nil, errors.New("AUTH_ENABLED is true but neither AUTH_API_TOKENS nor AUTH_JWKS_URL is set")
+	}
+	return chain, nil
+}
@@ -0,0 +1,34 @@
+package auth
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestQueryTokenKeepsTokenOutOfAccessLog(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var logs bytes.Buffer
+	router := gin.New()
+	router.Use(QueryToken(), gin.LoggerWithWriter(&logs))
+	authenticator, err := NewStaticTokenAuthenticator([]string{"alice:viewer:s3cret-token"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	router.GET("/stream", Middleware(authenticator), func(c *gin.Context) {
+		c.String(http.StatusOK, IdentityFrom(c).Subject+" "+c.Query("severity"))
+	})
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/stream?access_token=s3cret-token&severity=high", nil))
+	if rec.Code != http.StatusOK || rec.Body.String() != "alice high" {
+		t.Fatalf("status = %d, body = %q, want 200 for alice with the other query params kept", rec.Code, rec.Body)
+	}
+	if strings.Contains(logs.String(), "s3cret-token") {
+		t.Fatalf("access log contains the token: %s", logs.String())
+	}
+}
@@ -0,0 +1,230 @@
+package auth
+
+import (
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/go-jose/go-jose/v4/jwt"
+)
+
+// minJWKSRefetch limits refetches triggered by unknown key IDs
+const minJWKSRefetch = 30 * time.Second
+
+// JWTConfig configures bearer JWT validation
+type JWTConfig struct {
+	JWKSURL      string // https://... or file:///path/to/jwks.json
+	Issuer       string // required "iss"; at least one of Issuer and Audience must be set
+	Audience     string // required "aud" entry; at least one of Issuer and Audience must be set
+	RolesClaim   string // dotted path to the roles claim, e.g. "roles" or "realm_access.roles"
+	SubjectClaim string // dotted path to the caller's stable id (default "sub"); "email" is display only
+	Refresh      time.Duration
+	ClockSkew    time.Duration
+}
+
+// signatureAlgorithms are the JWS algs accepted from the JWKS keys
+var signatureAlgorithms = []jose.SignatureAlgorithm{jose.RS256, jose.RS384, jose.RS512, jose.ES256, jose.ES384}
+
+// JWTAuthenticator validates RS256/384/512 and ES256/384 JWTs against a JWKS.
+// Signature and claim checks are done by go-jose; this type only picks the key.
+type JWTAuthenticator struct {
+	cfg    JWTConfig
+	client *http.Client
+
+	mu        sync.RWMutex
+	keys      map[string]interface{}
+	fetchedAt time.Time
+}
+
+// NewJWTAuthenticator loads the JWKS once so misconfiguration fails at startup.
+// Without an issuer or audience any token signed by the identity provider would be
+// accepted, including ones minted for other applications, so one of them is required.
+func NewJWTAuthenticator(cfg JWTConfig) (*JWTAuthenticator, error) {
+	if cfg.Issuer == "" && cfg.Audience == "" {
+		return nil, errors.New("JWT validation requires AUTH_JWT_AUDIENCE or AUTH_JWT_ISSUER")
+	}
+	if cfg.RolesClaim == "" {
+		cfg.RolesClaim = "roles"
+	}
+	if cfg.SubjectClaim == "" {
+		cfg.SubjectClaim = "sub"
+	}
+	a := &JWTAuthenticator{
+		cfg:    cfg,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+	if err := a.refresh(); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+func (a *JWTAuthenticator) Authenticate(token string) (*Identity, error) {
+	parsed, err := jwt.ParseSigned(token, signatureAlgorithms)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+	key, err := a.key(parsed.Headers[0].KeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	var registered jwt.Claims
+	var claims map[string]interface{}
+	if err := parsed.Claims(key, &registered, &claims); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+	if err := a.validateClaims(registered); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+
+	role := HighestRole(claimStrings(lookupClaim(claims, a.cfg.RolesClaim)))
+	if role == "" {
+		return nil, fmt.Errorf("%w: no known role in claim %q", ErrInvalidToken, a.cfg.RolesClaim)
+	}
+
+	subject, _ := lookupClaim(claims, a.cfg.SubjectClaim).(string)
+	if subject == "" {
+		return nil, fmt.Errorf("%w: missing subject claim %q", ErrInvalidToken, a.cfg.SubjectClaim)
+	}
+	email, _ := claims["email"].(string)
+	return &Identity{Subject: subject, Email: email, Role: role, Method: "jwt"}, nil
+}
+
+func (a *JWTAuthenticator) validateClaims(claims jwt.Claims) error {
+	// go-jose는 exp가 없으면 검사를 건너뛰므로 직접 요구
+	if claims.Expiry == nil {
+		return errors.New("missing exp")
+	}
+	expected := jwt.Expected{Issuer: a.cfg.Issuer, Time: time.Now()}
+	if a.cfg.Audience != "" {
+		expected.AnyAudience = jwt.Audience{a.cfg.Audience}
+	}
+	return claims.ValidateWithLeeway(expected, a.cfg.ClockSkew)
+}
+
+// key returns the JWKS key for kid, refetching the JWKS when it is stale or
+// the kid is unknown (key rotation)
+func (a *JWTAuthenticator) key(kid string) (interface{}, error) {
+	a.mu.RLock()
+	key, ok := a.keys[kid]
+	stale := a.cfg.Refresh > 0 && time.Since(a.fetchedAt) > a.cfg.Refresh
+	recent := time.Since(a.fetchedAt) < minJWKSRefetch
+	a.mu.RUnlock()
+
+	if ok && !stale {
+		return key, nil
+	}
+	if !ok && recent {
+		return nil, fmt.Errorf("%w: unknown key id %q", ErrInvalidToken, kid)
+	}
+	if err := a.refresh(); err != nil {
+		log.Printf("WARN: Failed to refresh JWKS: %v", err)
+		if ok {
+			return key, nil
+		}
+		return nil, err
+	}
+
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if key, ok := a.keys[kid]; ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("%w: unknown key id %q", ErrInvalidToken, kid)
+}
+
+func (a *JWTAuthenticator) refresh() error {
+	raw, err := a.fetchJWKS()
+	if err != nil {
+		return err
+	}
+
+	var set jose.JSONWebKeySet
+	if err := json.Unmarshal(raw, &set); err != nil {
+		return fmt.Errorf("failed to parse JWKS: %w", err)
+	}
+
+	keys := make(map[string]interface{}, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		switch k.Key.(type) {
+		case *rsa.PublicKey, *ecdsa.PublicKey:
+			keys[k.KeyID] = k.Key
+		default:
+			log.Printf("WARN: Skipping JWKS key %q: unsupported key type %T", k.KeyID, k.Key)
+		}
+	}
+	if len(keys) == 0 {
+		return errors.New("JWKS contains no usable signing keys")
+	}
+
+	a.mu.Lock()
+	a.keys = keys
+	a.fetchedAt = time.Now()
+	a.mu.Unlock()
+	return nil
+}
+
+func (a *JWTAuthenticator) fetchJWKS() ([]byte, error) {
+	if path, ok := strings.CutPrefix(a.cfg.JWKSURL, "file://"); ok {
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read JWKS file: %w", err)
+		}
+		return raw, nil
+	}
+
+	resp, err := a.client.Get(a.cfg.JWKSURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch JWKS: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("JWKS endpoint returned status: %d", resp.StatusCode)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+}
+
+// lookupClaim resolves a dotted claim path such as "realm_access.roles"
+func lookupClaim(claims map[string]interface{}, path string) interface{} {
+	var current interface{} = claims
+	for _, part := range strings.Split(path, ".") {
+		m, ok := current.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		current = m[part]
+	}
+	return current
+}
+
+// claimStrings accepts a string or an array of strings
+func claimStrings(value interface{}) []string {
+	switch v := value.(type) {
+	case string:
+		return []string{v}
+	case []interface{}:
+		values := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	default:
+		return nil
+	}
+}
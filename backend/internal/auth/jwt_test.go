@@ -0,0 +1,291 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+)
+
+// jwksFixture holds the private halves of the keys published in a local JWKS file
+type jwksFixture struct {
+	url  string
+	rsa  *rsa.PrivateKey
+	p256 *ecdsa.PrivateKey
+	p384 *ecdsa.PrivateKey
+}
+
+func newJWKSFixture(t *testing.T) *jwksFixture {
+	t.Helper()
+	f := &jwksFixture{}
+	var err error
+	if f.rsa, err = rsa.GenerateKey(rand.Reader, 2048); err != nil {
+		t.Fatal(err)
+	}
+	if f.p256, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader); err != nil {
+		t.Fatal(err)
+	}
+	if f.p384, err = ecdsa.GenerateKey(elliptic.P384(), rand.Reader); err != nil {
+		t.Fatal(err)
+	}
+
+	set := jose.JSONWebKeySet{Keys: []jose.JSONWebKey{
+		{Key: &f.rsa.PublicKey, KeyID: "rsa", Use: "sig"},
+		{Key: &f.p256.PublicKey, KeyID: "p256", Use: "sig"},
+		{Key: &f.p384.PublicKey, KeyID: "p384", Use: "sig"},
+		{Key: &f.rsa.PublicKey, KeyID: "enc", Use: "enc"},
+	}}
+	raw, err := json.Marshal(set)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(path, raw, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	f.url = "file://" + path
+	return f
+}
+
+// sign builds a compact JWT with header {alg, kid}, signed by the fixture key kid
+func (f *jwksFixture) sign(t *testing.T, alg, kid string, claims map[string]interface{}) string {
+	t.Helper()
+	return f.signWith(t, alg, kid, kid, claims)
+}
+
+// signWith signs with the fixture key signKid using the hash alg names, whatever the
+// header kid says, so tests can pair an alg with a key it does not belong to
+func (f *jwksFixture) signWith(t *testing.T, alg, kid, signKid string, claims map[string]interface{}) string {
+	t.Helper()
+	segment := func(v interface{}) string {
+		raw, err := json.Marshal(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return base64.RawURLEncoding.EncodeToString(raw)
+	}
+	signed := segment(map[string]string{"alg": alg, "kid": kid, "typ": "JWT"}) + "." + segment(claims)
+
+	var hash crypto.Hash
+	var digest []byte
+	switch alg[2:] {
+	case "256":
+		sum := sha256.Sum256([]byte(signed))
+		hash, digest = crypto.SHA256, sum[:]
+	case "384":
+		sum := sha512.Sum384([]byte(signed))
+		hash, digest = crypto.SHA384, sum[:]
+	default:
+		sum := sha512.Sum512([]byte(signed))
+		hash, digest = crypto.SHA512, sum[:]
+	}
+
+	var signature []byte
+	switch signKid {
+	case "rsa", "enc":
+		var err error
+		if signature, err = rsa.SignPKCS1v15(rand.Reader, f.rsa, hash, digest); err != nil {
+			t.Fatal(err)
+		}
+	default:
+		key := f.p256
+		if signKid == "p384" {
+			key = f.p384
+		}
+		r, s, err := ecdsa.Sign(rand.Reader, key, digest)
+		if err != nil {
+			t.Fatal(err)
+		}
+		size := (key.Curve.Params().BitSize + 7) / 8
+		signature = append(r.FillBytes(make([]byte, size)), s.FillBytes(make([]byte, size))...)
+	}
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func validClaims() map[string]interface{} {
+	return map[string]interface{}{
+		"sub":   "user-1234",
+		"email": "alice@example.com",
+		"iss":   "https://issuer.example.com",
+		"aud":   []string{"admin-server", "other"},
+		"exp":   time.Now().Add(time.Hour).Unix(),
+		"roles": []string{"viewer", "operator"},
+	}
+}
+
+func newTestJWTAuthenticator(t *testing.T, f *jwksFixture, cfg JWTConfig) *JWTAuthenticator {
+	t.Helper()
+	cfg.JWKSURL = f.url
+	if cfg.Issuer == "" {
+		cfg.Issuer = "https://issuer.example.com"
+	}
+	if cfg.Audience == "" {
+		cfg.Audience = "admin-server"
+	}
+	a, err := NewJWTAuthenticator(cfg)
+	if err != nil {
+		t.Fatalf("NewJWTAuthenticator: %v", err)
+	}
+	return a
+}
+
+func TestJWTAcceptsEverySupportedAlg(t *testing.T) {
+	f := newJWKSFixture(t)
+	a := newTestJWTAuthenticator(t, f, JWTConfig{})
+
+	for _, tc := range []struct{ alg, kid string }{
+		{"RS256", "rsa"}, {"RS384", "rsa"}, {"RS512", "rsa"}, {"ES256", "p256"}, {"ES384", "p384"},
+	} {
+		identity, err := a.Authenticate(f.sign(t, tc.alg, tc.kid, validClaims()))
+		if err != nil {
+			t.Errorf("%s with key %s: %v", tc.alg, tc.kid, err)
+			continue
+		}
+		if identity.Subject != "user-1234" || identity.Role != RoleOperator || identity.Method != "jwt" {
+			t.Errorf("%s: identity = %+v", tc.alg, identity)
+		}
+	}
+}
+
+func TestJWTRejectsAlgKeyMismatch(t *testing.T) {
+	f := newJWKSFixture(t)
+	a := newTestJWTAuthenticator(t, f, JWTConfig{})
+
+	for _, tc := range []struct{ name, alg, headerKid, signKid string }{
+		// 서명 자체는 유효하지만 alg가 키의 곡선과 맞지 않음
+		{"ES256 against P-384 key", "ES256", "p384", "p384"},
+		{"ES384 against P-256 key", "ES384", "p256", "p256"},
+		{"RS256 against EC key", "RS256", "p256", "rsa"},
+		{"ES256 against RSA key", "ES256", "rsa", "p256"},
+		{"unsupported alg", "HS256", "rsa", "rsa"},
+		{"encryption key", "RS256", "enc", "enc"},
+	} {
+		token := f.signWith(t, tc.alg, tc.headerKid, tc.signKid, validClaims())
+		if _, err := a.Authenticate(token); !errors.Is(err, ErrInvalidToken) {
+			t.Errorf("%s: err = %v, want ErrInvalidToken", tc.name, err)
+		}
+	}
+}
+
+func TestJWTRejectsBadSignatureAndUnknownKey(t *testing.T) {
+	f := newJWKSFixture(t)
+	a := newTestJWTAuthenticator(t, f, JWTConfig{})
+
+	token := f.sign(t, "RS256", "rsa", validClaims())
+	parts := strings.Split(token, ".")
+	claims := validClaims()
+	claims["roles"] = []string{"rule-admin"}
+	forged := strings.Split(f.sign(t, "RS256", "rsa", claims), ".")[1]
+	if _, err := a.Authenticate(parts[0] + "." + forged + "." + parts[2]); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("tampered claims: err = %v, want ErrInvalidToken", err)
+	}
+
+	unknown := f.sign(t, "RS256", "rotated", validClaims())
+	if _, err := a.Authenticate(unknown); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("unknown kid: err = %v, want ErrInvalidToken", err)
+	}
+	if _, err := a.Authenticate("not-a-jwt"); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("malformed token: err = %v, want ErrInvalidToken", err)
+	}
+}
+
+func TestJWTValidatesClaims(t *testing.T) {
+	f := newJWKSFixture(t)
+	a := newTestJWTAuthenticator(t, f, JWTConfig{ClockSkew: time.Minute})
+
+	for _, tc := range []struct {
+		name   string
+		modify func(map[string]interface{})
+		valid  bool
+	}{
+		{"expired", func(c map[string]interface{}) { c["exp"] = time.Now().Add(-2 * time.Minute).Unix() }, false},
+		{"expired within skew", func(c map[string]interface{}) { c["exp"] = time.Now().Add(-30 * time.Second).Unix() }, true},
+		{"missing exp", func(c map[string]interface{}) { delete(c, "exp") }, false},
+		{"not yet valid", func(c map[string]interface{}) { c["nbf"] = time.Now().Add(10 * time.Minute).Unix() }, false},
+		{"wrong audience", func(c map[string]interface{}) { c["aud"] = []string{"another-service"} }, false},
+		{"single audience string", func(c map[string]interface{}) { c["aud"] = "admin-server" }, true},
+		{"missing audience", func(c map[string]interface{}) { delete(c, "aud") }, false},
+		{"wrong issuer", func(c map[string]interface{}) { c["iss"] = "https://evil.example.com" }, false},
+		{"missing issuer", func(c map[string]interface{}) { delete(c, "iss") }, false},
+		{"no known role", func(c map[string]interface{}) { c["roles"] = []string{"admin"} }, false},
+		{"missing subject", func(c map[string]interface{}) { delete(c, "sub") }, false},
+	} {
+		claims := validClaims()
+		tc.modify(claims)
+		_, err := a.Authenticate(f.sign(t, "ES256", "p256", claims))
+		if tc.valid && err != nil {
+			t.Errorf("%s: unexpected error %v", tc.name, err)
+		}
+		if !tc.valid && !errors.Is(err, ErrInvalidToken) {
+			t.Errorf("%s: err = %v, want ErrInvalidToken", tc.name, err)
+		}
+	}
+}
+
+func TestJWTSubjectIsNotReplacedByEmail(t *testing.T) {
+	f := newJWKSFixture(t)
+
+	identity, err := newTestJWTAuthenticator(t, f, JWTConfig{}).Authenticate(f.sign(t, "RS256", "rsa", validClaims()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if identity.Subject != "user-1234" || identity.Email != "alice@example.com" {
+		t.Fatalf("identity = %+v, want subject from sub and email for display", identity)
+	}
+
+	claims := validClaims()
+	claims["realm_access"] = map[string]interface{}{"roles": []string{"rule-admin"}}
+	claims["preferred_username"] = "alice"
+	a := newTestJWTAuthenticator(t, f, JWTConfig{RolesClaim: "realm_access.roles", SubjectClaim: "preferred_username"})
+	identity, err = a.Authenticate(f.sign(t, "RS256", "rsa", claims))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if identity.Subject != "alice" || identity.Role != RoleRuleAdmin {
+		t.Fatalf("identity = %+v, want subject from the configured claim", identity)
+	}
+}
+
+func TestNewJWTAuthenticatorFailsWithoutUsableKeys(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(path, []byte(`{"keys":[{"kty":"oct","kid":"hmac","k":"c2VjcmV0"}]}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewJWTAuthenticator(JWTConfig{JWKSURL: "file://" + path, Audience: "admin-server"}); err == nil {
+		t.Fatal("accepted a JWKS without RSA or EC signing keys")
+	}
+}
+
+func TestNewJWTAuthenticatorRequiresIssuerOrAudience(t *testing.T) {
+	f := newJWKSFixture(t)
+	if _, err := NewJWTAuthenticator(JWTConfig{JWKSURL: f.url}); err == nil {
+		t.Fatal("accepted a JWKS without an issuer or audience to pin tokens to this service")
+	}
+
+	// audience만 설정해도 다른 서비스용 토큰은 거절
+	a, err := NewJWTAuthenticator(JWTConfig{JWKSURL: f.url, Audience: "admin-server"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := a.Authenticate(f.sign(t, "RS256", "rsa", validClaims())); err != nil {
+		t.Fatalf("token for admin-server: %v", err)
+	}
+	claims := validClaims()
+	claims["aud"] = "another-app"
+	if _, err := a.Authenticate(f.sign(t, "RS256", "rsa", claims)); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("token for another app: err = %v, want ErrInvalidToken", err)
+	}
+}
@@ -0,0 +1,49 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"fmt"
+	"strings"
+)
+
+type staticToken struct {
+	hash     [32]byte
+	identity Identity
+}
+
+// StaticTokenAuthenticator accepts a fixed set of API tokens
+type StaticTokenAuthenticator struct {
+	tokens []staticToken
+}
+
+// NewStaticTokenAuthenticator parses entries of the form "name:role:token"
+func NewStaticTokenAuthenticator(entries []string) (*StaticTokenAuthenticator, error) {
+	a := &StaticTokenAuthenticator{}
+	for _, entry := range entries {
+		parts := strings.SplitN(entry, ":", 3)
+		if len(parts) != 3 || parts[0] == "" || parts[2] == "" {
+			return nil, fmt.Errorf("invalid API token entry (want name:role:token)")
+		}
+		if RoleRank(parts[1]) < 0 {
+			return nil, fmt.Errorf("API token %s has unknown role %q", parts[0], parts[1])
+		}
+		a.tokens = append(a.tokens, staticToken{
+			hash:     sha256.Sum256([]byte(parts[2])),
+			identity: Identity{Subject: parts[0], Role: parts[1], Method: "token"},
+		})
+	}
+	return a, nil
+}
+
+func (a *StaticTokenAuthenticator) Authenticate(token string) (*Identity, error) {
+	// 해시 비교로 토큰 길이와 무관하게 constant-time 비교
+	hash := sha256.Sum256([]byte(token))
+	for _, t := range a.tokens {
+		if subtle.ConstantTimeCompare(hash[:], t.hash[:]) == 1 {
+			identity := t.identity
+			return &identity, nil
+		}
+	}
+	return nil, ErrInvalidToken
+}
@@ -0,0 +1,84 @@
+package auth
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// WebhookTokenHeader carries the shared secret for POST /alerts/webhook
+	WebhookTokenHeader = "X-Webhook-Token"
+	// WebhookSignatureHeader carries "sha256=<hex HMAC-SHA256 of the body>"
+	WebhookSignatureHeader = "X-Webhook-Signature"
+)
+
+// maxWebhookBody bounds the body read for signature verification
+const maxWebhookBody = 1 << 20
+
+// WebhookMiddleware authenticates rule-engine webhooks with a shared token
+// and/or an HMAC signature. When both are configured either one is accepted.
+// When neither is configured the webhook fails closed with 503, unless
+// allowUnauthenticated (AUTH_ENABLED=false) leaves it open like the rest of the API.
+func WebhookMiddleware(token, hmacSecret string, allowUnauthenticated bool) gin.HandlerFunc {
+	if token == "" && hmacSecret == "" {
+		if allowUnauthenticated {
+			log.Println("WARN: WEBHOOK_TOKEN and WEBHOOK_HMAC_SECRET are unset and AUTH_ENABLED=false, webhooks are unauthenticated")
+			return func(c *gin.Context) { c.Next() }
+		}
+		log.Println("ERROR: WEBHOOK_TOKEN and WEBHOOK_HMAC_SECRET are unset, webhooks reject every request until one is configured")
+		return func(c *gin.Context) {
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{"error": "webhook authentication is not configured"})
+		}
+	}
+
+	return func(c *gin.Context) {
+		if token != "" {
+			provided := c.GetHeader(WebhookTokenHeader)
+			if provided == "" {
+				provided = bearerToken(c.GetHeader("Authorization"))
+			}
+			if provided != "" && subtle.ConstantTimeCompare([]byte(provided), []byte(token)) == 1 {
+				c.Next()
+				return
+			}
+		}
+
+		if hmacSecret != "" {
+			if signature := c.GetHeader(WebhookSignatureHeader); signature != "" {
+				body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxWebhookBody))
+				if err != nil {
+					c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "failed to read body"})
+					return
+				}
+				// 핸들러가 다시 읽을 수 있도록 body 복원
+				c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+				if validSignature(hmacSecret, body, signature) {
+					c.Next()
+					return
+				}
+			}
+		}
+
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid webhook credentials"})
+	}
+}
+
+func validSignature(secret string, body []byte, header string) bool {
+	provided, err := hex.DecodeString(strings.TrimPrefix(header, "sha256="))
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(provided, mac.Sum(nil))
+}
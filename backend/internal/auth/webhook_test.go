@@ -0,0 +1,70 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func webhookStatus(t *testing.T, middleware gin.HandlerFunc, body string, headers map[string]string) int {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/webhook", middleware, func(c *gin.Context) {
+		// 서명 검증 후에도 핸들러가 body를 그대로 읽을 수 있어야 함
+		raw, _ := c.GetRawData()
+		if string(raw) != body {
+			c.Status(http.StatusTeapot)
+			return
+		}
+		c.Status(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(body))
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec.Code
+}
+
+func TestWebhookMiddlewareFailsClosedWithoutSecrets(t *testing.T) {
+	if code := webhookStatus(t, WebhookMiddleware("", "", false), "{}", nil); code != http.StatusServiceUnavailable {
+		t.Fatalf("status = %d, want 503 while no webhook secret is configured", code)
+	}
+	if code := webhookStatus(t, WebhookMiddleware("", "", true), "{}", nil); code != http.StatusOK {
+		t.Fatalf("status = %d, want 200 with AUTH_ENABLED=false", code)
+	}
+}
+
+func TestWebhookMiddlewareChecksTokenAndSignature(t *testing.T) {
+	middleware := WebhookMiddleware("s3cret", "hmac-key", false)
+	body := `{"alert_id":"a1"}`
+	mac := hmac.New(sha256.New, []byte("hmac-key"))
+	mac.Write([]byte(body))
+	signature := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+
+	for _, tc := range []struct {
+		name    string
+		headers map[string]string
+		want    int
+	}{
+		{"token header", map[string]string{WebhookTokenHeader: "s3cret"}, http.StatusOK},
+		{"bearer token", map[string]string{"Authorization": "Bearer s3cret"}, http.StatusOK},
+		{"signature", map[string]string{WebhookSignatureHeader: signature}, http.StatusOK},
+		{"wrong token", map[string]string{WebhookTokenHeader: "guess"}, http.StatusUnauthorized},
+		{"wrong signature", map[string]string{WebhookSignatureHeader: "sha256=00"}, http.StatusUnauthorized},
+		{"no credentials", nil, http.StatusUnauthorized},
+	} {
+		if code := webhookStatus(t, middleware, body, tc.headers); code != tc.want {
+			t.Errorf("%s: status = %d, want %d", tc.name, code, tc.want)
+		}
+	}
+}
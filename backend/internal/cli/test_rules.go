@@ -0,0 +1,122 @@
+package cli
+
+import (
+	"admin_server/backend/internal/evaluator"
+	"admin_server/backend/internal/models"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Exit codes of the test-rules subcommand
+const (
+	ExitOK     = 0
+	ExitFailed = 1 // at least one rule test failed
+	ExitUsage  = 2 // bad arguments or unreadable rule file
+)
+
+// TestRules implements `admin-server test-rules [-json] FILE...`. Each FILE is a
+// rule.yaml (or its JSON form; "-" reads stdin) whose embedded rule tests are run
+// with the same evaluator as POST /api/v1/rules/test.
+func TestRules(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("test-rules", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	asJSON := flags.Bool("json", false, "print the test reports as JSON")
+	verbose := flags.Bool("v", false, "list passing tests as well")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "usage: admin-server test-rules [-json] [-v] FILE...")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return ExitUsage
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return ExitUsage
+	}
+
+	code := ExitOK
+	reports := make(map[string]*models.RuleTestReport, flags.NArg())
+	for _, path := range flags.Args() {
+		ruleSet, err := readRuleFile(path)
+		if err != nil {
+			fmt.Fprintf(stderr, "%s: %v\n", path, err)
+			return ExitUsage
+		}
+		report := evaluator.RunTests(ruleSet)
+		reports[path] = report
+		if report.Failed > 0 {
+			code = ExitFailed
+		}
+		if !*asJSON {
+			printReport(stdout, path, report, *verbose)
+		}
+	}
+
+	if *asJSON {
+		encoder := json.NewEncoder(stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(reports); err != nil {
+			fmt.Fprintf(stderr, "failed to encode reports: %v\n", err)
+			return ExitUsage
+		}
+	}
+	return code
+}
+
+// readRuleFile parses a rule file; YAML is a superset of JSON, so one decoder reads both
+func readRuleFile(path string) (*models.RuleSet, error) {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var ruleSet models.RuleSet
+	if err := yaml.Unmarshal(data, &ruleSet); err != nil {
+		return nil, fmt.Errorf("failed to parse rules: %w", err)
+	}
+	return &ruleSet, nil
+}
+
+func printReport(w io.Writer, path string, report *models.RuleTestReport, verbose bool) {
+	for _, result := range report.Results {
+		if result.Passed && !verbose {
+			continue
+		}
+		status := "PASS"
+		if !result.Passed {
+			status = "FAIL"
+		}
+		name := result.Name
+		if name == "" {
+			name = fmt.Sprintf("#%d", result.Index)
+		}
+
+		switch {
+		case result.Error != "":
+			fmt.Fprintf(w, "%s %s %s: %s\n", status, result.RuleID, name, result.Error)
+		case !result.Passed:
+			fmt.Fprintf(w, "%s %s %s: expected %s, got %s\n", status, result.RuleID, name, result.Expect, outcome(result.Matched))
+		default:
+			fmt.Fprintf(w, "%s %s %s\n", status, result.RuleID, name)
+		}
+	}
+	fmt.Fprintf(w, "%s (ruleset_version %s): %d passed, %d failed, %d total\n", path, report.RulesetVersion, report.Passed, report.Failed, report.Total)
+}
+
+func outcome(matched bool) string {
+	if matched {
+		return models.RuleTestMatch
+	}
+	return models.RuleTestNoMatch
+}
@@ -2,6 +2,9 @@ package config
 
 import (
 	"os"
+	"strconv"
+	"strings"
+	"time"
 )
 
 type Config struct {
@@ -9,11 +12,111 @@ type Config struct {
 	KubeConfigPath string
 	Namespace      string
 	ConfigMapName  string
+	ConfigMapKey   string // data key holding the RuleSet YAML
 	RuleYamlPath   string
 
+	// Rulesets registers further rule ConfigMaps, e.g. one per tenant namespace, as
+	// "name=namespace/configmap[/key]" entries. NAMESPACE/CONFIG_MAP_NAME is always
+	// registered as the "default" ruleset.
+	Rulesets []string
+
+	// CandidateConfigMapName holds the staged RuleSet the rule engine runs in shadow mode
+	CandidateConfigMapName string
+
+	// RuleTemplateConfigMapName holds the rule templates, in NAMESPACE
+	RuleTemplateConfigMapName string
+
+	// Rule revision history
+	RuleHistoryStore     string // "redis" (default) or "memory"
+	RuleHistoryRedisKey  string
+	RuleHistoryMaxLength int // oldest revisions beyond this are dropped (0 keeps all)
+
+	// RuleSyscallCheck controls the cluster_callable_syscalls cross-check:
+	// "warn" (default) reports unknown syscalls as warnings, "strict" rejects them, "off" skips it
+	RuleSyscallCheck string
+
+	// Per-rule edits and ruleset patches (read-modify-write against the live ConfigMap)
+	RuleVersionBump string // semver part bumped when an edit keeps ruleset_version: "major", "minor", "patch" (default) or "none"
+	RuleEditRetries int    // attempts before a write conflict is returned to the caller
+
+	// RuleSimulateMaxEvents caps the events one POST /rules/simulate evaluates
+	RuleSimulateMaxEvents int
+
+	// Rule engine reload notification after the live ConfigMap changes
+	RuleReloadNotifiers []string // any of "http", "rollout", "redis"; empty disables
+	RuleReloadURLs      []string // http: engine reload endpoints
+	RuleReloadSecret    string   // http: HMAC-SHA256 signing secret (optional)
+	RuleReloadRollouts  []string // rollout: "deployment/name" or "daemonset/name", optionally "namespace/kind/name"; "ruleset=" prefix for RULESETS entries
+	RuleReloadChannel   string   // redis: pub/sub channel
+	RuleReloadTimeout   time.Duration
+
+	// Rule engine instances report the ruleset they loaded
+	RuleEngineRedisKey   string
+	RuleEngineStaleAfter time.Duration // instances silent for longer are reported stale
+
 	// CCSL Redis 설정 (추가)
 	CCSLRedisAddr     string
 	CCSLRedisPassword string
+
+	// Alert storage configuration
+	AlertStore     string        // "redis" (default) or "memory"
+	AlertRedisKey  string        // key prefix for the alert sorted set and hash
+	AlertRetention time.Duration // alerts older than this are trimmed (0 disables)
+	AlertMaxCount  int           // maximum number of stored alerts (0 disables)
+
+	AlertStreamBuffer int // per-subscriber SSE buffer; slower subscribers are dropped
+
+	// Incident grouping: alerts with the same (rule_id, namespace, pod_name)
+	// arriving within IncidentWindow of the previous one join the same incident
+	IncidentRedisKey string
+	IncidentWindow   time.Duration
+
+	// Authentication. API tokens are "name:role:token" entries; roles are
+	// viewer, operator and rule-admin. JWTs are validated when AuthJWKSURL is set.
+	AuthEnabled       bool
+	AuthAPITokens     []string
+	AuthJWKSURL       string
+	AuthJWTIssuer     string
+	AuthJWTAudience   string
+	AuthRolesClaim    string
+	AuthSubjectClaim  string
+	AuthJWKSRefresh   time.Duration
+	WebhookToken      string // shared secret for POST /alerts/webhook
+	WebhookHMACSecret string // HMAC-SHA256 key for POST /alerts/webhook
+
+	// Alert silences
+	SilenceRedisKey        string
+	SilenceCleanupInterval time.Duration
+
+	// Kafka alert ingestion (optional, runs alongside the webhook)
+	KafkaEnabled    bool
+	KafkaBrokers    []string
+	KafkaAlertTopic string
+	KafkaGroupID    string
+	KafkaDLQTopic   string // undecodable messages are forwarded here (empty disables)
+
+	// Outbound alert notifications. A sink is enabled when its target is set;
+	// *Rules are comma-separated rule_id patterns (path.Match syntax, empty matches all).
+	NotifyQueueSize    int
+	NotifyMaxRetries   int
+	NotifyRetryBackoff time.Duration
+
+	SlackWebhookURL  string
+	SlackMinSeverity string
+	SlackRules       []string
+
+	NotifyWebhookURL         string
+	NotifyWebhookSecret      string // HMAC-SHA256 signing key (empty sends unsigned)
+	NotifyWebhookMinSeverity string
+	NotifyWebhookRules       []string
+
+	SMTPAddr        string
+	SMTPUsername    string
+	SMTPPassword    string
+	SMTPFrom        string
+	SMTPTo          []string
+	SMTPMinSeverity string
+	SMTPRules       []string
 }
 
 func Load() *Config {
@@ -21,10 +124,86 @@ func Load() *Config {
 		KubeConfigPath: getEnv("KUBE_CONFIG_PATH", ""),
 		Namespace:      getEnv("NAMESPACE", "default"),
 		ConfigMapName:  getEnv("CONFIG_MAP_NAME", "rule-yaml"),
+		ConfigMapKey:   getEnv("CONFIG_MAP_KEY", "rule.yaml"),
 		RuleYamlPath:   getEnv("RULE_YAML_FILE_PATH", "/etc/config/rule.yaml"),
 
+		Rulesets: getEnvList("RULESETS", ""),
+
+		CandidateConfigMapName: getEnv("CANDIDATE_CONFIG_MAP_NAME", "rule-yaml-candidate"),
+
+		RuleTemplateConfigMapName: getEnv("RULE_TEMPLATE_CONFIG_MAP_NAME", "rule-templates"),
+
+		RuleHistoryStore:      getEnv("RULE_HISTORY_STORE", "redis"),
+		RuleHistoryRedisKey:   getEnv("RULE_HISTORY_REDIS_KEY", "rules:history"),
+		RuleHistoryMaxLength:  getEnvInt("RULE_HISTORY_MAX_LENGTH", 100),
+		RuleSyscallCheck:      getEnv("RULE_SYSCALL_CHECK", "warn"),
+		RuleVersionBump:       getEnv("RULE_VERSION_BUMP", "patch"),
+		RuleEditRetries:       getEnvInt("RULE_EDIT_RETRIES", 5),
+		RuleSimulateMaxEvents: getEnvInt("RULE_SIMULATE_MAX_EVENTS", 10000),
+
+		RuleReloadNotifiers: getEnvList("RULE_RELOAD_NOTIFIERS", ""),
+		RuleReloadURLs:      getEnvList("RULE_RELOAD_URLS", ""),
+		RuleReloadSecret:    getEnv("RULE_RELOAD_SECRET", ""),
+		RuleReloadRollouts:  getEnvList("RULE_RELOAD_ROLLOUTS", ""),
+		RuleReloadChannel:   getEnv("RULE_RELOAD_CHANNEL", "rules:reload"),
+		RuleReloadTimeout:   getEnvDuration("RULE_RELOAD_TIMEOUT", 10*time.Second),
+
+		RuleEngineRedisKey:   getEnv("RULE_ENGINE_REDIS_KEY", "rules:engines"),
+		RuleEngineStaleAfter: getEnvDuration("RULE_ENGINE_STALE_AFTER", 5*time.Minute),
+
 		CCSLRedisAddr:     getEnv("CCSL_REDIS_ADDR", "redis-ccsl-svc:6379"),
 		CCSLRedisPassword: getEnv("CCSL_REDIS_PASSWORD", ""),
+
+		AlertStore:     getEnv("ALERT_STORE", "redis"),
+		AlertRedisKey:  getEnv("ALERT_REDIS_KEY", "alerts"),
+		AlertRetention: getEnvDuration("ALERT_RETENTION", 7*24*time.Hour),
+		AlertMaxCount:  getEnvInt("ALERT_MAX_COUNT", 10000),
+
+		AlertStreamBuffer: getEnvInt("ALERT_STREAM_BUFFER", 64),
+
+		IncidentRedisKey: getEnv("INCIDENT_REDIS_KEY", "incidents"),
+		IncidentWindow:   getEnvDuration("INCIDENT_WINDOW", 5*time.Minute),
+
+		AuthEnabled:       getEnvBool("AUTH_ENABLED", true),
+		AuthAPITokens:     getEnvList("AUTH_API_TOKENS", ""),
+		AuthJWKSURL:       getEnv("AUTH_JWKS_URL", ""),
+		AuthJWTIssuer:     getEnv("AUTH_JWT_ISSUER", ""),
+		AuthJWTAudience:   getEnv("AUTH_JWT_AUDIENCE", ""),
+		AuthRolesClaim:    getEnv("AUTH_ROLES_CLAIM", "roles"),
+		AuthSubjectClaim:  getEnv("AUTH_SUBJECT_CLAIM", "sub"),
+		AuthJWKSRefresh:   getEnvDuration("AUTH_JWKS_REFRESH", time.Hour),
+		WebhookToken:      getEnv("WEBHOOK_TOKEN", ""),
+		WebhookHMACSecret: getEnv("WEBHOOK_HMAC_SECRET", ""),
+
+		SilenceRedisKey:        getEnv("SILENCE_REDIS_KEY", "silences"),
+		SilenceCleanupInterval: getEnvDuration("SILENCE_CLEANUP_INTERVAL", time.Minute),
+
+		KafkaEnabled:    getEnvBool("KAFKA_ENABLED", false),
+		KafkaBrokers:    getEnvList("KAFKA_BROKERS", "kafka:9092"),
+		KafkaAlertTopic: getEnv("KAFKA_ALERT_TOPIC", "rule-engine-alerts"),
+		KafkaGroupID:    getEnv("KAFKA_GROUP_ID", "admin-server"),
+		KafkaDLQTopic:   getEnv("KAFKA_DLQ_TOPIC", "rule-engine-alerts-dlq"),
+
+		NotifyQueueSize:    getEnvInt("NOTIFY_QUEUE_SIZE", 256),
+		NotifyMaxRetries:   getEnvInt("NOTIFY_MAX_RETRIES", 3),
+		NotifyRetryBackoff: getEnvDuration("NOTIFY_RETRY_BACKOFF", time.Second),
+
+		SlackWebhookURL:  getEnv("SLACK_WEBHOOK_URL", ""),
+		SlackMinSeverity: getEnv("SLACK_MIN_SEVERITY", "high"),
+		SlackRules:       getEnvList("SLACK_RULES", ""),
+
+		NotifyWebhookURL:         getEnv("NOTIFY_WEBHOOK_URL", ""),
+		NotifyWebhookSecret:      getEnv("NOTIFY_WEBHOOK_SECRET", ""),
+		NotifyWebhookMinSeverity: getEnv("NOTIFY_WEBHOOK_MIN_SEVERITY", "info"),
+		NotifyWebhookRules:       getEnvList("NOTIFY_WEBHOOK_RULES", ""),
+
+		SMTPAddr:        getEnv("SMTP_ADDR", ""),
+		SMTPUsername:    getEnv("SMTP_USERNAME", ""),
+		SMTPPassword:    getEnv("SMTP_PASSWORD", ""),
+		SMTPFrom:        getEnv("SMTP_FROM", "admin-server@localhost"),
+		SMTPTo:          getEnvList("SMTP_TO", ""),
+		SMTPMinSeverity: getEnv("SMTP_MIN_SEVERITY", "critical"),
+		SMTPRules:       getEnvList("SMTP_RULES", ""),
 	}
 }
 
@@ -34,3 +213,41 @@ func getEnv(key, defaultValue string) string { //기본값 처리 위한 getEnv
 	}
 	return defaultValue
 }
+
+func getEnvInt(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := strconv.Atoi(value); err == nil {
+			return parsed
+		}
+	}
+	return defaultValue
+}
+
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := time.ParseDuration(value); err == nil {
+			return parsed
+		}
+	}
+	return defaultValue
+}
+
+func getEnvBool(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := strconv.ParseBool(value); err == nil {
+			return parsed
+		}
+	}
+	return defaultValue
+}
+
+// getEnvList splits a comma-separated value, dropping empty entries
+func getEnvList(key, defaultValue string) []string {
+	items := make([]string, 0)
+	for _, item := range strings.Split(getEnv(key, defaultValue), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,228 @@
+package consumer
+
+import (
+	"admin_server/backend/internal/config"
+	"admin_server/backend/internal/models"
+	"admin_server/backend/internal/services"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+const (
+	initialRetryDelay = 500 * time.Millisecond
+	maxRetryDelay     = 30 * time.Second
+)
+
+// MessageReader is the subset of *kafka.Reader used by the consumer
+type MessageReader interface {
+	FetchMessage(ctx context.Context) (kafka.Message, error)
+	CommitMessages(ctx context.Context, msgs ...kafka.Message) error
+	Close() error
+}
+
+// MessageWriter is the subset of *kafka.Writer used for the dead-letter topic
+type MessageWriter interface {
+	WriteMessages(ctx context.Context, msgs ...kafka.Message) error
+	Close() error
+}
+
+// AlertSink stores decoded alerts. AlertService satisfies it, so Kafka alerts
+// follow the same path as POST /api/v1/alerts/webhook.
+type AlertSink interface {
+	ReceiveWebhook(alert *models.WebhookAlert) error
+}
+
+// AlertConsumer reads rule-engine alerts from Kafka and hands them to an AlertSink.
+// Offsets are committed only after the alert is stored (or dead-lettered).
+type AlertConsumer struct {
+	reader MessageReader
+	dlq    MessageWriter // nil when no dead-letter topic is configured
+	sink   AlertSink
+}
+
+// NewAlertConsumer builds a consumer group reader for the configured alert topic
+func NewAlertConsumer(cfg *config.Config, sink AlertSink) *AlertConsumer {
+	reader := kafka.NewReader(kafka.ReaderConfig{
+		Brokers: cfg.KafkaBrokers,
+		Topic:   cfg.KafkaAlertTopic,
+		GroupID: cfg.KafkaGroupID,
+	})
+
+	var dlq MessageWriter
+	if cfg.KafkaDLQTopic != "" {
+		dlq = &kafka.Writer{
+			Addr:                   kafka.TCP(cfg.KafkaBrokers...),
+			Topic:                  cfg.KafkaDLQTopic,
+			Balancer:               &kafka.LeastBytes{},
+			AllowAutoTopicCreation: true,
+		}
+	}
+
+	return NewAlertConsumerWith(reader, dlq, sink)
+}
+
+// NewAlertConsumerWith wires a consumer from explicit reader/writer implementations
+func NewAlertConsumerWith(reader MessageReader, dlq MessageWriter, sink AlertSink) *AlertConsumer {
+	return &AlertConsumer{
+		reader: reader,
+		dlq:    dlq,
+		sink:   sink,
+	}
+}
+
+// Run consumes messages until ctx is cancelled
+func (c *AlertConsumer) Run(ctx context.Context) error {
+	log.Println("Kafka alert consumer started")
+	defer log.Println("Kafka alert consumer stopped")
+
+	for {
+		msg, err := c.reader.FetchMessage(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("failed to fetch Kafka message: %w", err)
+		}
+
+		if err := c.handle(ctx, msg); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+// Serve runs the consumer until ctx is cancelled, restarting Run with backoff when a
+// fetch fails (e.g. a broker outage) so ingestion never stays down
+func (c *AlertConsumer) Serve(ctx context.Context) {
+	delay := initialRetryDelay
+	for {
+		started := time.Now()
+		err := c.Run(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		// 한동안 정상 동작했다면 백오프를 처음부터 다시 시작
+		if time.Since(started) > maxRetryDelay {
+			delay = initialRetryDelay
+		}
+		log.Printf("ERROR: Kafka alert consumer failed, restarting in %s: %v", delay, err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+		delay = min(delay*2, maxRetryDelay)
+	}
+}
+
+// handle stores or dead-letters a single message and then commits its offset
+func (c *AlertConsumer) handle(ctx context.Context, msg kafka.Message) error {
+	alert, err := decodeAlert(msg.Value)
+	if err != nil {
+		log.Printf("WARN: Undecodable alert at %s[%d]@%d: %v", msg.Topic, msg.Partition, msg.Offset, err)
+		if err := c.deadLetter(ctx, msg, err); err != nil {
+			return err
+		}
+		return c.commit(ctx, msg)
+	}
+
+	// Storage failures are transient (e.g. Redis restart): retry the same
+	// message rather than committing past it.
+	delay := initialRetryDelay
+	for {
+		err := c.sink.ReceiveWebhook(alert)
+		if err == nil {
+			break
+		}
+		if errors.Is(err, services.ErrInvalidAlert) {
+			// 재시도해도 성공하지 않으므로 디코딩 실패와 같이 dead-letter 처리
+			if err := c.deadLetter(ctx, msg, err); err != nil {
+				return err
+			}
+			return c.commit(ctx, msg)
+		}
+		log.Printf("ERROR: Failed to store Kafka alert %s, retrying in %s: %v", alert.AlertID, delay, err)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+		delay = min(delay*2, maxRetryDelay)
+	}
+
+	return c.commit(ctx, msg)
+}
+
+// commit retries a failed offset commit in place with backoff. Giving up would leave
+// the message uncommitted without re-delivering it: the reader does not fetch it again
+// within the same group session, and later commits would move past it.
+func (c *AlertConsumer) commit(ctx context.Context, msg kafka.Message) error {
+	delay := initialRetryDelay
+	for {
+		err := c.reader.CommitMessages(ctx, msg)
+		if err == nil {
+			return nil
+		}
+		log.Printf("ERROR: Failed to commit Kafka offset %d, retrying in %s: %v", msg.Offset, delay, err)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+		delay = min(delay*2, maxRetryDelay)
+	}
+}
+
+// deadLetter forwards the raw message with the decode error attached as headers
+func (c *AlertConsumer) deadLetter(ctx context.Context, msg kafka.Message, cause error) error {
+	if c.dlq == nil {
+		log.Printf("WARN: No dead-letter topic configured, dropping message at offset %d", msg.Offset)
+		return nil
+	}
+
+	headers := append([]kafka.Header{}, msg.Headers...)
+	headers = append(headers,
+		kafka.Header{Key: "x-dlq-error", Value: []byte(cause.Error())},
+		kafka.Header{Key: "x-dlq-source-topic", Value: []byte(msg.Topic)},
+		kafka.Header{Key: "x-dlq-source-offset", Value: []byte(fmt.Sprint(msg.Offset))},
+	)
+
+	err := c.dlq.WriteMessages(ctx, kafka.Message{
+		Key:     msg.Key,
+		Value:   msg.Value,
+		Headers: headers,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write message to dead-letter topic: %w", err)
+	}
+	return nil
+}
+
+// Close releases the reader and the dead-letter writer
+func (c *AlertConsumer) Close() error {
+	err := c.reader.Close()
+	if c.dlq != nil {
+		err = errors.Join(err, c.dlq.Close())
+	}
+	return err
+}
+
+// decodeAlert parses a message and applies the same checks as the HTTP webhook
+func decodeAlert(value []byte) (*models.WebhookAlert, error) {
+	var alert models.WebhookAlert
+	if err := json.Unmarshal(value, &alert); err != nil {
+		return nil, fmt.Errorf("invalid alert JSON: %w", err)
+	}
+	if err := services.ValidateWebhookAlert(&alert); err != nil {
+		return nil, err
+	}
+	return &alert, nil
+}
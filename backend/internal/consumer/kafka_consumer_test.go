@@ -0,0 +1,315 @@
+package consumer
+
+import (
+	"admin_server/backend/internal/models"
+	"admin_server/backend/internal/services"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// fakeBroker is an in-process stand-in for one topic partition: the consumer reads
+// from it, commits to it and dead-letters into it. Every step is recorded in events
+// so tests can check their order.
+type fakeBroker struct {
+	mu        sync.Mutex
+	messages  chan kafka.Message
+	fetchErrs []error // returned by FetchMessage, one per call, before messages are served
+	commitErrs []error // returned by CommitMessages, one per call, before commits succeed
+	committed []int64
+	dlq       []kafka.Message
+	events    []string
+}
+
+func newFakeBroker(values ...string) *fakeBroker {
+	b := &fakeBroker{messages: make(chan kafka.Message, len(values))}
+	for i, value := range values {
+		b.messages <- kafka.Message{Topic: "alerts", Offset: int64(i), Value: []byte(value)}
+	}
+	return b
+}
+
+func (b *fakeBroker) record(event string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.events = append(b.events, event)
+}
+
+func (b *fakeBroker) FetchMessage(ctx context.Context) (kafka.Message, error) {
+	b.mu.Lock()
+	if len(b.fetchErrs) > 0 {
+		err := b.fetchErrs[0]
+		b.fetchErrs = b.fetchErrs[1:]
+		b.mu.Unlock()
+		b.record("fetch-error")
+		return kafka.Message{}, err
+	}
+	b.mu.Unlock()
+
+	select {
+	case msg := <-b.messages:
+		return msg, nil
+	case <-ctx.Done():
+		return kafka.Message{}, ctx.Err()
+	}
+}
+
+func (b *fakeBroker) CommitMessages(ctx context.Context, msgs ...kafka.Message) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if len(b.commitErrs) > 0 {
+		err := b.commitErrs[0]
+		b.commitErrs = b.commitErrs[1:]
+		b.events = append(b.events, "commit-error")
+		return err
+	}
+	for _, msg := range msgs {
+		b.committed = append(b.committed, msg.Offset)
+		b.events = append(b.events, fmt.Sprintf("commit %d", msg.Offset))
+	}
+	return nil
+}
+
+func (b *fakeBroker) WriteMessages(ctx context.Context, msgs ...kafka.Message) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.dlq = append(b.dlq, msgs...)
+	for range msgs {
+		b.events = append(b.events, "dlq")
+	}
+	return nil
+}
+
+func (b *fakeBroker) Close() error { return nil }
+
+func (b *fakeBroker) snapshot() (committed []int64, dlq []kafka.Message, events []string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]int64(nil), b.committed...), append([]kafka.Message(nil), b.dlq...), append([]string(nil), b.events...)
+}
+
+// fakeSink fails the first failures calls, then stores alerts
+type fakeSink struct {
+	broker   *fakeBroker
+	mu       sync.Mutex
+	failures int
+	stored   []string
+}
+
+func (s *fakeSink) ReceiveWebhook(alert *models.WebhookAlert) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.failures > 0 {
+		s.failures--
+		s.broker.record("store-error " + alert.AlertID)
+		return errors.New("redis unavailable")
+	}
+	s.stored = append(s.stored, alert.AlertID)
+	s.broker.record("store " + alert.AlertID)
+	return nil
+}
+
+// waitFor polls cond until it holds or the test times out
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func alertJSON(id string) string {
+	return fmt.Sprintf(`{"alert_id":%q,"rule_id":"R1","severity":"high"}`, id)
+}
+
+func TestCommitsOffsetOnlyAfterAlertIsStored(t *testing.T) {
+	broker := newFakeBroker(alertJSON("a1"), alertJSON("a2"))
+	sink := &fakeSink{broker: broker, failures: 1}
+	consumer := NewAlertConsumerWith(broker, broker, sink)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error, 1)
+	go func() { done <- consumer.Run(ctx) }()
+
+	waitFor(t, "both offsets to be committed", func() bool {
+		committed, _, _ := broker.snapshot()
+		return len(committed) == 2
+	})
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("Run returned %v after cancel, want nil", err)
+	}
+
+	_, dlq, events := broker.snapshot()
+	want := []string{"store-error a1", "store a1", "commit 0", "store a2", "commit 1"}
+	if fmt.Sprint(events) != fmt.Sprint(want) {
+		t.Fatalf("events = %v, want %v", events, want)
+	}
+	if len(dlq) != 0 {
+		t.Fatalf("stored alerts were dead-lettered: %v", dlq)
+	}
+}
+
+func TestDoesNotCommitWhenStoringIsCancelled(t *testing.T) {
+	broker := newFakeBroker(alertJSON("a1"))
+	sink := &fakeSink{broker: broker, failures: 1000}
+	consumer := NewAlertConsumerWith(broker, broker, sink)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- consumer.Run(ctx) }()
+
+	waitFor(t, "the first store attempt", func() bool {
+		_, _, events := broker.snapshot()
+		return len(events) > 0
+	})
+	cancel()
+	<-done
+
+	if committed, _, _ := broker.snapshot(); len(committed) != 0 {
+		t.Fatalf("committed %v although the alert was never stored", committed)
+	}
+}
+
+func TestUndecodableMessagesGoToDeadLetterTopic(t *testing.T) {
+	broker := newFakeBroker("{not json", `{"alert_id":"no-rule"}`, alertJSON("ok"))
+	sink := &fakeSink{broker: broker}
+	consumer := NewAlertConsumerWith(broker, broker, sink)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error, 1)
+	go func() { done <- consumer.Run(ctx) }()
+
+	waitFor(t, "all offsets to be committed", func() bool {
+		committed, _, _ := broker.snapshot()
+		return len(committed) == 3
+	})
+	cancel()
+	<-done
+
+	_, dlq, events := broker.snapshot()
+	want := []string{"dlq", "commit 0", "dlq", "commit 1", "store ok", "commit 2"}
+	if fmt.Sprint(events) != fmt.Sprint(want) {
+		t.Fatalf("events = %v, want %v", events, want)
+	}
+	if len(dlq) != 2 || string(dlq[0].Value) != "{not json" {
+		t.Fatalf("dead-lettered %d messages, want the 2 undecodable ones", len(dlq))
+	}
+	headers := map[string]string{}
+	for _, h := range dlq[1].Headers {
+		headers[h.Key] = string(h.Value)
+	}
+	if headers["x-dlq-source-topic"] != "alerts" || headers["x-dlq-source-offset"] != "1" || headers["x-dlq-error"] == "" {
+		t.Fatalf("dead-letter headers = %v", headers)
+	}
+	if fmt.Sprint(sink.stored) != "[ok]" {
+		t.Fatalf("stored %v, want only the valid alert", sink.stored)
+	}
+}
+
+// A failed commit must be retried for the same message: the reader will not hand it
+// out again, and committing a later offset first would skip it for good
+func TestRetriesFailedCommitBeforeTheNextMessage(t *testing.T) {
+	broker := newFakeBroker(alertJSON("a1"), alertJSON("a2"))
+	broker.commitErrs = []error{errors.New("coordinator not available"), errors.New("rebalance in progress")}
+	consumer := NewAlertConsumerWith(broker, nil, &fakeSink{broker: broker})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error, 1)
+	go func() { done <- consumer.Run(ctx) }()
+
+	waitFor(t, "both offsets to be committed", func() bool {
+		committed, _, _ := broker.snapshot()
+		return len(committed) == 2
+	})
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("Run returned %v, want nil: commit errors are retried, not returned", err)
+	}
+
+	committed, _, events := broker.snapshot()
+	want := []string{"store a1", "commit-error", "commit-error", "commit 0", "store a2", "commit 1"}
+	if fmt.Sprint(events) != fmt.Sprint(want) || fmt.Sprint(committed) != "[0 1]" {
+		t.Fatalf("events = %v, committed = %v, want %v", events, committed, want)
+	}
+}
+
+func TestDeadLettersAlertsTheSinkRejects(t *testing.T) {
+	broker := newFakeBroker(alertJSON("bad"), alertJSON("ok"))
+	sink := &rejectingSink{broker: broker, reject: "bad"}
+	consumer := NewAlertConsumerWith(broker, broker, sink)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error, 1)
+	go func() { done <- consumer.Run(ctx) }()
+
+	waitFor(t, "both offsets to be committed", func() bool {
+		committed, _, _ := broker.snapshot()
+		return len(committed) == 2
+	})
+	cancel()
+	<-done
+
+	_, _, events := broker.snapshot()
+	want := []string{"dlq", "commit 0", "store ok", "commit 1"}
+	if fmt.Sprint(events) != fmt.Sprint(want) {
+		t.Fatalf("events = %v, want %v (a rejected alert is not retried)", events, want)
+	}
+}
+
+// rejectingSink returns services.ErrInvalidAlert for the alert_id reject
+type rejectingSink struct {
+	broker *fakeBroker
+	reject string
+}
+
+func (s *rejectingSink) ReceiveWebhook(alert *models.WebhookAlert) error {
+	if alert.AlertID == s.reject {
+		return fmt.Errorf("%w: rejected by the sink", services.ErrInvalidAlert)
+	}
+	s.broker.record("store " + alert.AlertID)
+	return nil
+}
+
+func TestServeRestartsAfterFetchError(t *testing.T) {
+	broker := newFakeBroker(alertJSON("a1"))
+	broker.fetchErrs = []error{errors.New("broker unreachable")}
+	sink := &fakeSink{broker: broker}
+	consumer := NewAlertConsumerWith(broker, nil, sink)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		consumer.Serve(ctx)
+		close(done)
+	}()
+
+	waitFor(t, "the alert to be committed after the restart", func() bool {
+		committed, _, _ := broker.snapshot()
+		return len(committed) == 1
+	})
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Serve did not return after ctx was cancelled")
+	}
+
+	_, _, events := broker.snapshot()
+	want := []string{"fetch-error", "store a1", "commit 0"}
+	if fmt.Sprint(events) != fmt.Sprint(want) {
+		t.Fatalf("events = %v, want %v", events, want)
+	}
+}
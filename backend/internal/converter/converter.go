@@ -0,0 +1,184 @@
+package converter
+
+import (
+	"admin_server/backend/internal/models"
+	"bytes"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Import formats
+const (
+	FormatYAML     = "yaml"
+	FormatJSON     = "json"
+	FormatFalco    = "falco"
+	FormatTetragon = "tetragon"
+)
+
+// Formats lists the import formats in detection order
+var Formats = []string{FormatYAML, FormatJSON, FormatFalco, FormatTetragon}
+
+// ErrUnknownFormat is returned for a format name outside Formats or a file Detect cannot place
+var ErrUnknownFormat = errors.New("unknown rule file format")
+
+// Result is a converted RuleSet and what could not be carried over
+type Result struct {
+	RuleSet      *models.RuleSet
+	Untranslated []models.UntranslatedConstruct
+}
+
+func (r *Result) untranslated(source, construct, reason string) {
+	r.Untranslated = append(r.Untranslated, models.UntranslatedConstruct{
+		Source:    source,
+		Construct: construct,
+		Reason:    reason,
+	})
+}
+
+// Convert reads data in format (detected when empty) into a RuleSet
+func Convert(data []byte, format string) (*Result, string, error) {
+	if format == "" {
+		format = Detect(data)
+	}
+	var result *Result
+	var err error
+	switch format {
+	case FormatYAML, FormatJSON:
+		result, err = convertNative(data)
+	case FormatFalco:
+		result, err = ConvertFalco(data)
+	case FormatTetragon:
+		result, err = ConvertTetragon(data)
+	default:
+		return nil, format, fmt.Errorf("%w %q (supported: %s)", ErrUnknownFormat, format, strings.Join(Formats, ", "))
+	}
+	return result, format, err
+}
+
+// Detect guesses the format of a rule file: a Tetragon TracingPolicy by its kind,
+// a Falco rules file by its top-level list of rule/macro/list items, otherwise this
+// server's own RuleSet as JSON or YAML
+func Detect(data []byte) string {
+	trimmed := bytes.TrimSpace(data)
+	if bytes.HasPrefix(trimmed, []byte("{")) {
+		return FormatJSON
+	}
+
+	var probe interface{}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	if err := decoder.Decode(&probe); err != nil {
+		return ""
+	}
+	switch doc := probe.(type) {
+	case map[string]interface{}:
+		if kind, _ := doc["kind"].(string); strings.HasPrefix(kind, "TracingPolicy") {
+			return FormatTetragon
+		}
+		return FormatYAML
+	case []interface{}:
+		for _, item := range doc {
+			if entry, ok := item.(map[string]interface{}); ok {
+				for _, key := range []string{"rule", "macro", "list"} {
+					if _, ok := entry[key]; ok {
+						return FormatFalco
+					}
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// convertNative reads this server's RuleSet; YAML is a superset of JSON, so one decoder reads both
+func convertNative(data []byte) (*Result, error) {
+	var ruleSet models.RuleSet
+	if err := yaml.Unmarshal(data, &ruleSet); err != nil {
+		return nil, fmt.Errorf("failed to parse rules: %w", err)
+	}
+	return &Result{RuleSet: &ruleSet}, nil
+}
+
+var nonIDChars = regexp.MustCompile(`[^A-Z0-9]+`)
+
+// ruleID turns a free-form name into a rule_id such as FALCO_TERMINAL_SHELL_IN_CONTAINER
+func ruleID(prefix, name string) string {
+	id := nonIDChars.ReplaceAllString(strings.ToUpper(name), "_")
+	return prefix + "_" + strings.Trim(id, "_")
+}
+
+// flatten turns a converted condition into a rule's condition list, unwrapping a
+// top-level all-group
+func flatten(cond *models.Condition) []models.Condition {
+	if cond == nil {
+		return nil
+	}
+	if cond.GroupKind() == models.ConditionAll && !cond.IsLeaf() {
+		return cond.All
+	}
+	return []models.Condition{*cond}
+}
+
+// group combines converted children, dropping the untranslated (nil) ones and
+// splicing in the children of groups of the same kind
+func group(kind string, children []*models.Condition) *models.Condition {
+	var kept []models.Condition
+	for _, child := range children {
+		switch {
+		case child == nil:
+		case kind == models.ConditionAny && child.GroupKind() == models.ConditionAny && !child.IsLeaf():
+			kept = append(kept, child.Any...)
+		case kind == models.ConditionAll && child.GroupKind() == models.ConditionAll && !child.IsLeaf():
+			kept = append(kept, child.All...)
+		default:
+			kept = append(kept, *child)
+		}
+	}
+	switch {
+	case len(kept) == 0:
+		return nil
+	case len(kept) == 1:
+		return &kept[0]
+	case kind == models.ConditionAny:
+		return &models.Condition{Any: kept}
+	default:
+		return &models.Condition{All: kept}
+	}
+}
+
+// globToRegex translates a shell glob (*, ? and [...]) into an anchored regex
+func globToRegex(glob string) string {
+	var b strings.Builder
+	b.WriteString("^")
+	for i := 0; i < len(glob); i++ {
+		switch c := glob[i]; c {
+		case '*':
+			b.WriteString(".*")
+		case '?':
+			b.WriteString(".")
+		case '[':
+			end := strings.IndexByte(glob[i:], ']')
+			if end < 0 {
+				b.WriteString(`\[`)
+				continue
+			}
+			b.WriteString(glob[i : i+end+1])
+			i += end
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	b.WriteString("$")
+	return b.String()
+}
+
+// syscallName strips kernel symbol prefixes: __x64_sys_openat and sys_openat become openat
+func syscallName(symbol string) string {
+	if i := strings.Index(symbol, "sys_"); i >= 0 && (i == 0 || strings.HasSuffix(symbol[:i], "_")) {
+		return symbol[i+len("sys_"):]
+	}
+	return symbol
+}
@@ -0,0 +1,182 @@
+package converter
+
+import (
+	"admin_server/backend/internal/evaluator"
+	"admin_server/backend/internal/models"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// convertFixture converts testdata/name with format detection and checks the detected format
+func convertFixture(t *testing.T, name, wantFormat string) *Result {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, format, err := Convert(data, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if format != wantFormat {
+		t.Fatalf("detected format %q, want %q", format, wantFormat)
+	}
+	return result
+}
+
+func ruleIDs(ruleSet *models.RuleSet) []string {
+	ids := make([]string, len(ruleSet.Rules))
+	for i, rule := range ruleSet.Rules {
+		ids[i] = rule.RuleID
+	}
+	return ids
+}
+
+func findRule(t *testing.T, ruleSet *models.RuleSet, id string) models.Rule {
+	t.Helper()
+	for _, rule := range ruleSet.Rules {
+		if rule.RuleID == id {
+			return rule
+		}
+	}
+	t.Fatalf("rule %s was not converted", id)
+	return models.Rule{}
+}
+
+// checkUntranslated compares the reported constructs as "source | construct" in report order
+func checkUntranslated(t *testing.T, result *Result, want []string) {
+	t.Helper()
+	got := make([]string, len(result.Untranslated))
+	for i, u := range result.Untranslated {
+		if u.Reason == "" {
+			t.Errorf("%s | %s reported without a reason", u.Source, u.Construct)
+		}
+		got[i] = u.Source + " | " + u.Construct
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("untranslated =\n  %s\nwant\n  %s", strings.Join(got, "\n  "), strings.Join(want, "\n  "))
+	}
+}
+
+func checkMatch(t *testing.T, rule models.Rule, event evaluator.Event, want bool) {
+	t.Helper()
+	got, err := evaluator.Match(rule.Conditions, event)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != want {
+		t.Fatalf("%s on %v = %v, want %v", rule.RuleID, event, got, want)
+	}
+}
+
+func TestConvertFalcoFixture(t *testing.T) {
+	result := convertFixture(t, "falco_rules.yaml", FormatFalco)
+
+	wantIDs := []string{"FALCO_TERMINAL_SHELL_IN_CONTAINER", "FALCO_READ_SENSITIVE_FILE", "FALCO_UNKNOWN_MACRO", "FALCO_EXCEPTIONS"}
+	if got := ruleIDs(result.RuleSet); !reflect.DeepEqual(got, wantIDs) {
+		t.Fatalf("rules = %v, want %v", got, wantIDs)
+	}
+
+	shell := findRule(t, result.RuleSet, "FALCO_TERMINAL_SHELL_IN_CONTAINER")
+	if shell.Severity != "medium" || !reflect.DeepEqual(shell.Tags, []string{"container", "shell", "mitre_execution"}) {
+		t.Fatalf("severity %q, tags %v; want medium and the tags with spaces replaced", shell.Severity, shell.Tags)
+	}
+	// 리스트의 append는 항목을 더하고, override한 매크로는 이전 정의를 유지함
+	container := evaluator.Event{"syscall_name": "execve", "container_id": "abc123", "comm": "zsh"}
+	checkMatch(t, shell, container, true)
+	checkMatch(t, shell, evaluator.Event{"syscall_name": "execve", "container_id": "host", "comm": "zsh"}, false)
+	checkMatch(t, shell, evaluator.Event{"syscall_name": "execve", "container_id": "abc123", "comm": "python"}, false)
+
+	sensitive := findRule(t, result.RuleSet, "FALCO_READ_SENSITIVE_FILE")
+	if sensitive.Enabled == nil || *sensitive.Enabled || sensitive.Severity != "high" {
+		t.Fatalf("enabled %v, severity %q; want disabled and high", sensitive.Enabled, sensitive.Severity)
+	}
+	checkMatch(t, sensitive, evaluator.Event{"syscall_name": "openat", "path": "/etc/gshadow", "comm": "vim"}, true)
+	checkMatch(t, sensitive, evaluator.Event{"syscall_name": "openat", "path": "/root/.ssh/id_rsa", "comm": "CAT"}, true)
+	checkMatch(t, sensitive, evaluator.Event{"syscall_name": "openat", "path": "/etcetera/shadow", "comm": "cat"}, false)
+
+	checkUntranslated(t, result, []string{
+		`macro "container" | override`,
+		`rule "Terminal shell in container" | proc.pname = sshd`,
+		`rule "Read sensitive file" | evt.arg.flags contains O_RDONLY`,
+		`rule "Read sensitive file" | append/override`,
+		`rule "Unknown macro" | undefined_macro`,
+		`rule "Unknown macro" | priority: loud`,
+		`rule "Only unsupported fields" | proc.aname[2] = containerd`,
+		`rule "Only unsupported fields" | proc.aname[2] = containerd`,
+		`rule "Exceptions" | exceptions`,
+		`rule "Broken syntax" | evt.type = (execve`,
+		`rule "Audit event" | source: k8s_audit`,
+	})
+}
+
+func TestConvertTetragonFixture(t *testing.T) {
+	result := convertFixture(t, "tetragon_policy.yaml", FormatTetragon)
+
+	wantIDs := []string{
+		"TETRAGON_FILE_MONITORING_LIST_OPEN_CALLS",
+		"TETRAGON_FILE_MONITORING_X64_SYS_SETUID",
+		"TETRAGON_FILE_MONITORING_SYS_ENTER_PTRACE",
+		"TETRAGON_TENANT_EXEC_SYS_EXECVE",
+	}
+	if got := ruleIDs(result.RuleSet); !reflect.DeepEqual(got, wantIDs) {
+		t.Fatalf("rules = %v, want %v", got, wantIDs)
+	}
+
+	open := findRule(t, result.RuleSet, "TETRAGON_FILE_MONITORING_LIST_OPEN_CALLS")
+	if open.Action != models.RuleActionKill {
+		t.Fatalf("action %q, want the strongest selector action kill", open.Action)
+	}
+	checkMatch(t, open, evaluator.Event{"syscall_name": "openat", "args": []interface{}{-100, "/etc/shadow"}, "exe": "/bin/cat", "pid": 42}, true)
+	checkMatch(t, open, evaluator.Event{"syscall_name": "openat", "args": []interface{}{-100, "/etc/shadow"}, "exe": "/usr/sbin/sshd", "pid": 42}, false)
+	checkMatch(t, open, evaluator.Event{"syscall_name": "open", "args": []interface{}{-100, "/tmp/x"}, "exe": "/sbin/init", "pid": 1}, true)
+
+	setuid := findRule(t, result.RuleSet, "TETRAGON_FILE_MONITORING_X64_SYS_SETUID")
+	if setuid.Action != models.RuleActionBlock {
+		t.Fatalf("action %q, want block for Override", setuid.Action)
+	}
+	checkMatch(t, setuid, evaluator.Event{"syscall_name": "setuid", "args.0": "0x0"}, true)
+
+	tenant := findRule(t, result.RuleSet, "TETRAGON_TENANT_EXEC_SYS_EXECVE")
+	checkMatch(t, tenant, evaluator.Event{"namespace": "tenant-a", "syscall_name": "execve", "args.0": "/usr/bin/nc"}, true)
+	checkMatch(t, tenant, evaluator.Event{"namespace": "tenant-b", "syscall_name": "execve", "args.0": "/usr/bin/nc"}, false)
+
+	checkUntranslated(t, result, []string{
+		`policy "file-monitoring" | uprobes`,
+		`policy "file-monitoring" | lsmhooks`,
+		`policy "file-monitoring" kprobe "__x64_sys_setuid" selector 0 | matchCapabilities`,
+		`policy "file-monitoring" kprobe "__x64_sys_setuid" selector 0 | matchActions: NotifyEnforcer`,
+		`policy "file-monitoring" kprobe "list:generated" | list:generated`,
+		`policy "file-monitoring" kprobe "list:missing" | list:missing`,
+		`policy "file-monitoring" kprobe "security_file_open" | security_file_open`,
+		`policy "file-monitoring" tracepoint syscalls/sys_enter_ptrace selector 0 | matchArgs`,
+		`policy "file-monitoring" tracepoint syscalls/sys_enter_ptrace selector 1 | matchNamespaceChanges`,
+		`policy "file-monitoring" tracepoint syscalls/sys_enter_ptrace | selectors`,
+		`policy "file-monitoring" tracepoint sched/sched_process_exec | sched/sched_process_exec`,
+		`policy "tenant-exec" | podSelector`,
+		`policy "tenant-exec" kprobe "sys_execve" selector 0 | matchReturnActions`,
+		`ConfigMap not-a-policy | kind: ConfigMap`,
+	})
+}
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		data string
+		want string
+	}{
+		{`{"ruleset_version": "1.0.0"}`, FormatJSON},
+		{"ruleset_version: 1.0.0\nrules: []\n", FormatYAML},
+		{"- macro: m\n  condition: evt.type = execve\n", FormatFalco},
+		{"kind: TracingPolicyNamespaced\n", FormatTetragon},
+		{"- just\n- a list\n", ""},
+		{"key: [unclosed", ""},
+	}
+	for _, tt := range tests {
+		if got := Detect([]byte(tt.data)); got != tt.want {
+			t.Errorf("Detect(%q) = %q, want %q", tt.data, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,486 @@
+package converter
+
+import (
+	"admin_server/backend/internal/evaluator"
+	"admin_server/backend/internal/models"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// falcoFields maps Falco filter fields to condition fields
+var falcoFields = map[string]string{
+	"evt.type":                   "syscall_name",
+	"evt.res":                    "return_value",
+	"evt.rawres":                 "return_value",
+	"proc.name":                  "comm",
+	"proc.exe":                   "exe",
+	"proc.exepath":               "exe",
+	"proc.pid":                   "pid",
+	"proc.ppid":                  "ppid",
+	"user.uid":                   "uid",
+	"group.gid":                  "gid",
+	"fd.name":                    "path",
+	"fs.path.name":               "path",
+	"container.id":               "container_id",
+	"container.name":             "container_name",
+	"container.image":            "image",
+	"container.image.repository": "image",
+	"k8s.pod.name":               "pod_name",
+	"k8s.ns.name":                "namespace",
+}
+
+// falcoSeverities maps Falco priorities to SeverityLevels
+var falcoSeverities = map[string]string{
+	"emergency":     "critical",
+	"alert":         "critical",
+	"critical":      "critical",
+	"error":         "high",
+	"warning":       "medium",
+	"notice":        "low",
+	"informational": "info",
+	"info":          "info",
+	"debug":         "info",
+}
+
+// falcoItem is one entry of a Falco rules file
+type falcoItem struct {
+	Rule       string        `yaml:"rule"`
+	Macro      string        `yaml:"macro"`
+	List       string        `yaml:"list"`
+	Desc       string        `yaml:"desc"`
+	Source     string        `yaml:"source"`
+	Condition  string        `yaml:"condition"`
+	Items      []interface{} `yaml:"items"`
+	Priority   string        `yaml:"priority"`
+	Tags       []string      `yaml:"tags"`
+	Enabled    *bool         `yaml:"enabled"`
+	Append     bool          `yaml:"append"`
+	Override   yaml.Node     `yaml:"override"`
+	Exceptions yaml.Node     `yaml:"exceptions"`
+}
+
+// ConvertFalco converts the syscall rules of a Falco rules file, expanding macros and
+// lists (including appends to them).
+// Conditions on fields or operators without an equivalent are dropped and reported,
+// which can make a rule match more (dropped from an and) or less (dropped from an or).
+func ConvertFalco(data []byte) (*Result, error) {
+	var items []falcoItem
+	if err := yaml.Unmarshal(data, &items); err != nil {
+		return nil, fmt.Errorf("failed to parse Falco rules: %w", err)
+	}
+
+	f := &falcoConverter{
+		result: &Result{RuleSet: &models.RuleSet{Description: "Imported from Falco rules"}},
+		macros: make(map[string]string),
+		lists:  make(map[string][]interface{}),
+	}
+	for _, item := range items {
+		switch {
+		case item.Macro != "":
+			f.defineMacro(item)
+		case item.List != "":
+			f.defineList(item)
+		}
+	}
+
+	for _, item := range items {
+		if item.Rule == "" {
+			continue
+		}
+		f.convertRule(item)
+	}
+	return f.result, nil
+}
+
+type falcoConverter struct {
+	result *Result
+	macros map[string]string
+	lists  map[string][]interface{}
+	source string   // rule being converted, for reports
+	stack  []string // macros being expanded, to stop cycles
+}
+
+// defineMacro records a macro; append: true extends an earlier definition the way
+// Falco does, by joining the condition text
+func (f *falcoConverter) defineMacro(item falcoItem) {
+	if !item.Override.IsZero() {
+		f.result.untranslated(fmt.Sprintf("macro %q", item.Macro), "override", "overriding a macro is not supported; the earlier definition is kept")
+		return
+	}
+	if existing, ok := f.macros[item.Macro]; ok && item.Append {
+		f.macros[item.Macro] = existing + " " + item.Condition
+		return
+	}
+	f.macros[item.Macro] = item.Condition
+}
+
+// defineList records a list; append: true adds its items to an earlier definition
+func (f *falcoConverter) defineList(item falcoItem) {
+	if !item.Override.IsZero() {
+		f.result.untranslated(fmt.Sprintf("list %q", item.List), "override", "overriding a list is not supported; the earlier definition is kept")
+		return
+	}
+	if item.Append {
+		f.lists[item.List] = append(f.lists[item.List], item.Items...)
+		return
+	}
+	f.lists[item.List] = item.Items
+}
+
+func (f *falcoConverter) convertRule(item falcoItem) {
+	f.source = fmt.Sprintf("rule %q", item.Rule)
+	if item.Source != "" && item.Source != "syscall" {
+		f.result.untranslated(f.source, "source: "+item.Source, "only syscall rules can be converted")
+		return
+	}
+	if item.Append || !item.Override.IsZero() {
+		f.result.untranslated(f.source, "append/override", "rules amending an earlier rule are not supported")
+		return
+	}
+	if !item.Exceptions.IsZero() {
+		f.result.untranslated(f.source, "exceptions", "exceptions are not supported; add them as not conditions")
+	}
+
+	expr, err := parseFalcoCondition(item.Condition)
+	if err != nil {
+		f.result.untranslated(f.source, item.Condition, err.Error())
+		return
+	}
+	conditions := flatten(f.convert(expr))
+	if len(conditions) == 0 {
+		f.result.untranslated(f.source, item.Condition, "no condition could be translated; rule skipped")
+		return
+	}
+
+	rule := models.Rule{
+		RuleID:      ruleID("FALCO", item.Rule),
+		Description: strings.TrimSpace(item.Desc),
+		Enabled:     item.Enabled,
+		Conditions:  conditions,
+	}
+	if item.Priority != "" {
+		if severity, ok := falcoSeverities[strings.ToLower(item.Priority)]; ok {
+			rule.Severity = severity
+		} else {
+			f.result.untranslated(f.source, "priority: "+item.Priority, "unknown priority")
+		}
+	}
+	for _, tag := range item.Tags {
+		rule.Tags = append(rule.Tags, strings.ReplaceAll(strings.TrimSpace(tag), " ", "_"))
+	}
+	f.result.RuleSet.Rules = append(f.result.RuleSet.Rules, rule)
+}
+
+// convert translates a parsed expression; nil means nothing could be translated
+func (f *falcoConverter) convert(expr *falcoExpr) *models.Condition {
+	switch expr.kind {
+	case "and", "or":
+		children := make([]*models.Condition, len(expr.children))
+		for i, child := range expr.children {
+			children[i] = f.convert(child)
+		}
+		if expr.kind == "or" {
+			return group(models.ConditionAny, children)
+		}
+		return group(models.ConditionAll, children)
+	case "not":
+		child := f.convert(expr.children[0])
+		if child == nil {
+			return nil
+		}
+		return &models.Condition{Not: child}
+	case "macro":
+		return f.expandMacro(expr.name)
+	default:
+		return f.convertComparison(expr)
+	}
+}
+
+func (f *falcoConverter) expandMacro(name string) *models.Condition {
+	condition, ok := f.macros[name]
+	if !ok {
+		f.result.untranslated(f.source, name, "unknown macro")
+		return nil
+	}
+	for _, active := range f.stack {
+		if active == name {
+			f.result.untranslated(f.source, name, "macro refers to itself")
+			return nil
+		}
+	}
+	expr, err := parseFalcoCondition(condition)
+	if err != nil {
+		f.result.untranslated(f.source, "macro "+name, err.Error())
+		return nil
+	}
+	f.stack = append(f.stack, name)
+	defer func() { f.stack = f.stack[:len(f.stack)-1] }()
+	return f.convert(expr)
+}
+
+func (f *falcoConverter) convertComparison(expr *falcoExpr) *models.Condition {
+	construct := strings.TrimSpace(expr.field + " " + expr.op + " " + strings.Join(expr.values, ", "))
+	field, ok := falcoFields[expr.field]
+	if !ok {
+		f.result.untranslated(f.source, construct, fmt.Sprintf("field %s has no equivalent", expr.field))
+		return nil
+	}
+
+	switch expr.op {
+	case "=", "!=":
+		operator := evaluator.OpEquals
+		if expr.op == "!=" {
+			operator = evaluator.OpNotEquals
+		}
+		return &models.Condition{Field: field, Operator: operator, Value: falcoLiteral(expr.values[0])}
+	case "<", "<=", ">", ">=":
+		operator := map[string]string{"<": evaluator.OpLT, "<=": evaluator.OpLTE, ">": evaluator.OpGT, ">=": evaluator.OpGTE}[expr.op]
+		return &models.Condition{Field: field, Operator: operator, Value: falcoLiteral(expr.values[0])}
+	case "in":
+		return &models.Condition{Field: field, Operator: evaluator.OpIn, Value: f.expandLists(expr.values, nil)}
+	case "startswith", "endswith", "contains":
+		operator := map[string]string{"startswith": evaluator.OpPrefix, "endswith": evaluator.OpSuffix, "contains": evaluator.OpContains}[expr.op]
+		return &models.Condition{Field: field, Operator: operator, Value: expr.values[0]}
+	case "icontains":
+		return &models.Condition{Field: field, Operator: evaluator.OpRegex, Value: "(?i)" + regexp.QuoteMeta(expr.values[0])}
+	case "glob":
+		return &models.Condition{Field: field, Operator: evaluator.OpRegex, Value: globToRegex(expr.values[0])}
+	case "regex":
+		return &models.Condition{Field: field, Operator: evaluator.OpRegex, Value: expr.values[0]}
+	case "pmatch":
+		// 경로 접두어 목록: 목록의 경로 자체 또는 그 하위 경로와 일치
+		var any []models.Condition
+		for _, item := range f.expandLists(expr.values, nil) {
+			prefix := strings.TrimSuffix(fmt.Sprint(item), "/")
+			any = append(any,
+				models.Condition{Field: field, Operator: evaluator.OpEquals, Value: prefix},
+				models.Condition{Field: field, Operator: evaluator.OpPrefix, Value: prefix + "/"})
+		}
+		return &models.Condition{Any: any}
+	}
+	f.result.untranslated(f.source, construct, fmt.Sprintf("operator %s has no equivalent", expr.op))
+	return nil
+}
+
+// expandLists replaces list names with their items, recursively
+func (f *falcoConverter) expandLists(values []string, seen map[string]bool) []interface{} {
+	items := make([]interface{}, 0, len(values))
+	for _, value := range values {
+		list, ok := f.lists[value]
+		if !ok || seen[value] {
+			items = append(items, falcoLiteral(value))
+			continue
+		}
+		if seen == nil {
+			seen = make(map[string]bool)
+		}
+		seen[value] = true
+		names := make([]string, len(list))
+		for i, item := range list {
+			names[i] = fmt.Sprint(item)
+		}
+		items = append(items, f.expandLists(names, seen)...)
+	}
+	return items
+}
+
+// falcoLiteral reads integers as numbers and everything else as strings
+func falcoLiteral(value string) interface{} {
+	if n, err := strconv.Atoi(value); err == nil {
+		return n
+	}
+	return value
+}
+
+// falcoExpr is a parsed Falco condition
+type falcoExpr struct {
+	kind     string // and, or, not, macro or compare
+	children []*falcoExpr
+	name     string // macro name
+	field    string
+	op       string
+	values   []string
+}
+
+var falcoComparisonOps = map[string]bool{
+	"=": true, "==": true, "!=": true, "<": true, "<=": true, ">": true, ">=": true,
+	"in": true, "intersects": true, "pmatch": true,
+	"startswith": true, "bstartswith": true, "endswith": true, "contains": true,
+	"icontains": true, "bcontains": true, "glob": true, "iglob": true, "regex": true, "exists": true,
+}
+
+// falcoListOps take a parenthesised list
+var falcoListOps = map[string]bool{"in": true, "intersects": true, "pmatch": true}
+
+func parseFalcoCondition(condition string) (*falcoExpr, error) {
+	tokens, err := tokenizeFalco(condition)
+	if err != nil {
+		return nil, err
+	}
+	p := &falcoParser{tokens: tokens}
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected %q", p.tokens[p.pos].text)
+	}
+	return expr, nil
+}
+
+type falcoToken struct {
+	text   string
+	quoted bool
+}
+
+func tokenizeFalco(s string) ([]falcoToken, error) {
+	var tokens []falcoToken
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(' || c == ')' || c == ',':
+			tokens = append(tokens, falcoToken{text: string(c)})
+			i++
+		case c == '"' || c == '\'':
+			end := strings.IndexByte(s[i+1:], c)
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated string at offset %d", i)
+			}
+			tokens = append(tokens, falcoToken{text: s[i+1 : i+1+end], quoted: true})
+			i += end + 2
+		case c == '=' || c == '!' || c == '<' || c == '>':
+			op := string(c)
+			if i+1 < len(s) && s[i+1] == '=' {
+				op += "="
+			}
+			if op == "!" {
+				return nil, fmt.Errorf("unexpected ! at offset %d", i)
+			}
+			tokens = append(tokens, falcoToken{text: op})
+			i += len(op)
+		default:
+			start := i
+			for i < len(s) && !strings.ContainsRune(" \t\n\r(),\"'=!<>", rune(s[i])) {
+				i++
+			}
+			tokens = append(tokens, falcoToken{text: s[start:i]})
+		}
+	}
+	return tokens, nil
+}
+
+type falcoParser struct {
+	tokens []falcoToken
+	pos    int
+}
+
+func (p *falcoParser) peek() (falcoToken, bool) {
+	if p.pos >= len(p.tokens) {
+		return falcoToken{}, false
+	}
+	return p.tokens[p.pos], true
+}
+
+func (p *falcoParser) keyword(word string) bool {
+	token, ok := p.peek()
+	if ok && !token.quoted && token.text == word {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *falcoParser) parseOr() (*falcoExpr, error) {
+	return p.parseBinary("or", p.parseAnd)
+}
+
+func (p *falcoParser) parseAnd() (*falcoExpr, error) {
+	return p.parseBinary("and", p.parseUnary)
+}
+
+func (p *falcoParser) parseBinary(kind string, operand func() (*falcoExpr, error)) (*falcoExpr, error) {
+	first, err := operand()
+	if err != nil {
+		return nil, err
+	}
+	children := []*falcoExpr{first}
+	for p.keyword(kind) {
+		next, err := operand()
+		if err != nil {
+			return nil, err
+		}
+		children = append(children, next)
+	}
+	if len(children) == 1 {
+		return first, nil
+	}
+	return &falcoExpr{kind: kind, children: children}, nil
+}
+
+func (p *falcoParser) parseUnary() (*falcoExpr, error) {
+	if p.keyword("not") {
+		child, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &falcoExpr{kind: "not", children: []*falcoExpr{child}}, nil
+	}
+	if p.keyword("(") {
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.keyword(")") {
+			return nil, fmt.Errorf("missing )")
+		}
+		return expr, nil
+	}
+
+	token, ok := p.peek()
+	if !ok {
+		return nil, fmt.Errorf("unexpected end of condition")
+	}
+	p.pos++
+	op, ok := p.peek()
+	if !ok || op.quoted || !falcoComparisonOps[op.text] {
+		return &falcoExpr{kind: "macro", name: token.text}, nil
+	}
+	p.pos++
+
+	expr := &falcoExpr{kind: "compare", field: token.text, op: op.text}
+	if expr.op == "==" {
+		expr.op = "="
+	}
+	switch {
+	case expr.op == "exists":
+	case falcoListOps[expr.op]:
+		if !p.keyword("(") {
+			return nil, fmt.Errorf("%s %s needs a parenthesised list", expr.field, expr.op)
+		}
+		for !p.keyword(")") {
+			value, ok := p.peek()
+			if !ok {
+				return nil, fmt.Errorf("missing ) after %s %s", expr.field, expr.op)
+			}
+			p.pos++
+			if value.text == "," && !value.quoted {
+				continue
+			}
+			expr.values = append(expr.values, value.text)
+		}
+	default:
+		value, ok := p.peek()
+		if !ok {
+			return nil, fmt.Errorf("%s %s needs a value", expr.field, expr.op)
+		}
+		p.pos++
+		expr.values = []string{value.text}
+	}
+	return expr, nil
+}
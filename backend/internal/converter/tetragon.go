@@ -0,0 +1,340 @@
+package converter
+
+import (
+	"admin_server/backend/internal/evaluator"
+	"admin_server/backend/internal/models"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// tetragonPolicy is the part of a TracingPolicy the converter reads
+type tetragonPolicy struct {
+	Kind     string `yaml:"kind"`
+	Metadata struct {
+		Name      string `yaml:"name"`
+		Namespace string `yaml:"namespace"`
+	} `yaml:"metadata"`
+	Spec struct {
+		KProbes           []tetragonHook `yaml:"kprobes"`
+		Tracepoints       []tetragonHook `yaml:"tracepoints"`
+		UProbes           []yaml.Node    `yaml:"uprobes"`
+		USDTs             []yaml.Node    `yaml:"usdts"`
+		LSMHooks          []yaml.Node    `yaml:"lsmhooks"`
+		PodSelector       yaml.Node      `yaml:"podSelector"`
+		ContainerSelector yaml.Node      `yaml:"containerSelector"`
+		Lists             []struct {
+			Name   string   `yaml:"name"`
+			Values []string `yaml:"values"`
+		} `yaml:"lists"`
+	} `yaml:"spec"`
+}
+
+type tetragonHook struct {
+	Call      string             `yaml:"call"`
+	Subsystem string             `yaml:"subsystem"`
+	Event     string             `yaml:"event"`
+	Syscall   *bool              `yaml:"syscall"`
+	Args      []tetragonArg      `yaml:"args"`
+	Selectors []tetragonSelector `yaml:"selectors"`
+}
+
+type tetragonArg struct {
+	Index int    `yaml:"index"`
+	Type  string `yaml:"type"`
+}
+
+type tetragonSelector struct {
+	MatchArgs              []tetragonMatch `yaml:"matchArgs"`
+	MatchReturnArgs        []tetragonMatch `yaml:"matchReturnArgs"`
+	MatchBinaries          []tetragonMatch `yaml:"matchBinaries"`
+	MatchPIDs              []tetragonMatch `yaml:"matchPIDs"`
+	MatchNamespaces        []yaml.Node     `yaml:"matchNamespaces"`
+	MatchNamespaceChanges  []yaml.Node     `yaml:"matchNamespaceChanges"`
+	MatchCapabilities      []yaml.Node     `yaml:"matchCapabilities"`
+	MatchCapabilityChanges []yaml.Node     `yaml:"matchCapabilityChanges"`
+	MatchReturnActions     []yaml.Node     `yaml:"matchReturnActions"`
+	MatchActions           []struct {
+		Action string `yaml:"action"`
+	} `yaml:"matchActions"`
+}
+
+type tetragonMatch struct {
+	Index    *int     `yaml:"index"`
+	Operator string   `yaml:"operator"`
+	Values   []string `yaml:"values"`
+}
+
+// tetragonActions maps matchActions to rule actions, strongest first
+var tetragonActions = []struct{ tetragon, action string }{
+	{"sigkill", models.RuleActionKill},
+	{"override", models.RuleActionBlock},
+	{"post", models.RuleActionAlert},
+}
+
+// ConvertTetragon converts the kprobes and syscall tracepoints of one or more
+// TracingPolicy documents, one rule per hook. Selectors become an any-group of
+// their match* filters; filters and hooks without an equivalent are reported.
+func ConvertTetragon(data []byte) (*Result, error) {
+	result := &Result{RuleSet: &models.RuleSet{Description: "Imported from Tetragon TracingPolicy"}}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	for {
+		var policy tetragonPolicy
+		err := decoder.Decode(&policy)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse TracingPolicy: %w", err)
+		}
+		if policy.Kind == "" {
+			continue // empty document
+		}
+		if !strings.HasPrefix(policy.Kind, "TracingPolicy") {
+			result.untranslated(policy.Kind+" "+policy.Metadata.Name, "kind: "+policy.Kind, "not a TracingPolicy")
+			continue
+		}
+		convertTetragonPolicy(result, &policy)
+	}
+	return result, nil
+}
+
+func convertTetragonPolicy(result *Result, policy *tetragonPolicy) {
+	name := policy.Metadata.Name
+	source := fmt.Sprintf("policy %q", name)
+	if len(policy.Spec.UProbes) > 0 {
+		result.untranslated(source, "uprobes", "user-space probes have no syscall equivalent")
+	}
+	if len(policy.Spec.USDTs) > 0 {
+		result.untranslated(source, "usdts", "user-space probes have no syscall equivalent")
+	}
+	if len(policy.Spec.LSMHooks) > 0 {
+		result.untranslated(source, "lsmhooks", "LSM hooks have no syscall equivalent")
+	}
+	// 파드/컨테이너 범위가 빠지면 룰이 모든 워크로드에 적용되므로 보고
+	if !policy.Spec.PodSelector.IsZero() {
+		result.untranslated(source, "podSelector", "pod label selectors have no equivalent; the rules match every pod")
+	}
+	if !policy.Spec.ContainerSelector.IsZero() {
+		result.untranslated(source, "containerSelector", "container selectors have no equivalent; the rules match every container")
+	}
+
+	lists := make(map[string][]string, len(policy.Spec.Lists))
+	for _, list := range policy.Spec.Lists {
+		lists[list.Name] = list.Values
+	}
+
+	var scope []models.Condition
+	if policy.Kind == "TracingPolicyNamespaced" && policy.Metadata.Namespace != "" {
+		scope = append(scope, models.Condition{Field: "namespace", Operator: evaluator.OpEquals, Value: policy.Metadata.Namespace})
+	}
+
+	for _, hook := range policy.Spec.KProbes {
+		hookSource := fmt.Sprintf("%s kprobe %q", source, hook.Call)
+		var syscall models.Condition
+		if list, ok := strings.CutPrefix(hook.Call, "list:"); ok {
+			values, ok := lists[list]
+			if !ok {
+				result.untranslated(hookSource, hook.Call, "unknown list")
+				continue
+			}
+			if len(values) == 0 {
+				result.untranslated(hookSource, hook.Call, "list has no values (generated lists are not supported)")
+				continue
+			}
+			names := make([]interface{}, len(values))
+			for i, value := range values {
+				names[i] = syscallName(value)
+			}
+			syscall = models.Condition{Field: "syscall_name", Operator: evaluator.OpIn, Value: names}
+		} else {
+			if (hook.Syscall == nil || !*hook.Syscall) && syscallName(hook.Call) == hook.Call {
+				result.untranslated(hookSource, hook.Call, "kernel function is not a syscall")
+				continue
+			}
+			syscall = models.Condition{Field: "syscall_name", Operator: evaluator.OpEquals, Value: syscallName(hook.Call)}
+		}
+		addTetragonRule(result, hookSource, name+"_"+hook.Call, scope, syscall, hook)
+	}
+
+	for _, hook := range policy.Spec.Tracepoints {
+		hookSource := fmt.Sprintf("%s tracepoint %s/%s", source, hook.Subsystem, hook.Event)
+		call, ok := strings.CutPrefix(hook.Event, "sys_enter_")
+		if !ok {
+			call, ok = strings.CutPrefix(hook.Event, "sys_exit_")
+		}
+		if hook.Subsystem != "syscalls" || !ok {
+			result.untranslated(hookSource, hook.Subsystem+"/"+hook.Event, "only syscalls/sys_enter_* and sys_exit_* tracepoints are supported")
+			continue
+		}
+		syscall := models.Condition{Field: "syscall_name", Operator: evaluator.OpEquals, Value: call}
+		addTetragonRule(result, hookSource, name+"_"+hook.Event, scope, syscall, hook)
+	}
+}
+
+func addTetragonRule(result *Result, source, name string, scope []models.Condition, syscall models.Condition, hook tetragonHook) {
+	rule := models.Rule{
+		RuleID:      ruleID("TETRAGON", name),
+		Description: "Converted from Tetragon " + strings.Trim(source, " "),
+		Conditions:  append(append([]models.Condition{}, scope...), syscall),
+	}
+
+	var selectors []*models.Condition
+	action := ""
+	for i, selector := range hook.Selectors {
+		selectorSource := fmt.Sprintf("%s selector %d", source, i)
+		selectors = append(selectors, convertTetragonSelector(result, selectorSource, selector))
+		for _, a := range selector.MatchActions {
+			if !tetragonActionKnown(a.Action) {
+				result.untranslated(selectorSource, "matchActions: "+a.Action, "action has no equivalent")
+			}
+			action = strongerAction(action, a.Action)
+		}
+	}
+	// 필터가 없는(또는 변환되지 않은) 선택자는 훅 전체와 일치하므로 OR 전체가 무의미해진다
+	for i, s := range selectors {
+		if s == nil {
+			if hasTetragonFilters(hook.Selectors[i]) {
+				result.untranslated(source, "selectors", "a selector could not be translated; the rule matches the whole hook")
+			}
+			selectors = nil
+			break
+		}
+	}
+	if cond := group(models.ConditionAny, selectors); cond != nil {
+		rule.Conditions = append(rule.Conditions, flatten(cond)...)
+	}
+	rule.Action = action
+	result.RuleSet.Rules = append(result.RuleSet.Rules, rule)
+}
+
+// convertTetragonSelector ANDs the filters of one selector; nil means the selector
+// had no translatable filter (it then matches everything)
+func convertTetragonSelector(result *Result, source string, selector tetragonSelector) *models.Condition {
+	var filters []*models.Condition
+	for _, m := range selector.MatchArgs {
+		if m.Index == nil {
+			result.untranslated(source, "matchArgs", "argument filter without index")
+			continue
+		}
+		filters = append(filters, convertTetragonMatch(result, source, "matchArgs", fmt.Sprintf("args.%d", *m.Index), m))
+	}
+	for _, m := range selector.MatchReturnArgs {
+		filters = append(filters, convertTetragonMatch(result, source, "matchReturnArgs", "return_value", m))
+	}
+	for _, m := range selector.MatchBinaries {
+		filters = append(filters, convertTetragonMatch(result, source, "matchBinaries", "exe", m))
+	}
+	for _, m := range selector.MatchPIDs {
+		filters = append(filters, convertTetragonMatch(result, source, "matchPIDs", "pid", m))
+	}
+	if len(selector.MatchNamespaces) > 0 {
+		result.untranslated(source, "matchNamespaces", "Linux namespace filters have no equivalent")
+	}
+	if len(selector.MatchNamespaceChanges) > 0 {
+		result.untranslated(source, "matchNamespaceChanges", "Linux namespace filters have no equivalent")
+	}
+	if len(selector.MatchCapabilities) > 0 {
+		result.untranslated(source, "matchCapabilities", "capability filters have no equivalent")
+	}
+	if len(selector.MatchCapabilityChanges) > 0 {
+		result.untranslated(source, "matchCapabilityChanges", "capability filters have no equivalent")
+	}
+	if len(selector.MatchReturnActions) > 0 {
+		result.untranslated(source, "matchReturnActions", "return actions have no equivalent")
+	}
+	return group(models.ConditionAll, filters)
+}
+
+func hasTetragonFilters(selector tetragonSelector) bool {
+	return len(selector.MatchArgs)+len(selector.MatchReturnArgs)+len(selector.MatchBinaries)+
+		len(selector.MatchPIDs)+len(selector.MatchNamespaces)+len(selector.MatchNamespaceChanges)+
+		len(selector.MatchCapabilities)+len(selector.MatchCapabilityChanges) > 0
+}
+
+func convertTetragonMatch(result *Result, source, filter, field string, m tetragonMatch) *models.Condition {
+	construct := fmt.Sprintf("%s %s %s", filter, m.Operator, strings.Join(m.Values, ","))
+	if len(m.Values) == 0 {
+		result.untranslated(source, construct, "filter without values")
+		return nil
+	}
+	values := make([]interface{}, len(m.Values))
+	for i, v := range m.Values {
+		values[i] = tetragonLiteral(v)
+	}
+
+	switch m.Operator {
+	case "Equal", "In":
+		if len(values) == 1 {
+			return &models.Condition{Field: field, Operator: evaluator.OpEquals, Value: values[0]}
+		}
+		return &models.Condition{Field: field, Operator: evaluator.OpIn, Value: values}
+	case "NotEqual", "NotIn":
+		if len(values) == 1 {
+			return &models.Condition{Field: field, Operator: evaluator.OpNotEquals, Value: values[0]}
+		}
+		return &models.Condition{Field: field, Operator: evaluator.OpNotIn, Value: values}
+	case "Prefix", "Postfix", "NotPrefix", "NotPostfix":
+		operator := evaluator.OpPrefix
+		if strings.HasSuffix(m.Operator, "Postfix") {
+			operator = evaluator.OpSuffix
+		}
+		any := make([]*models.Condition, len(m.Values))
+		for i, v := range m.Values {
+			any[i] = &models.Condition{Field: field, Operator: operator, Value: v}
+		}
+		cond := group(models.ConditionAny, any)
+		if strings.HasPrefix(m.Operator, "Not") {
+			return &models.Condition{Not: cond}
+		}
+		return cond
+	case "GT", "LT":
+		operator := evaluator.OpGT
+		if m.Operator == "LT" {
+			operator = evaluator.OpLT
+		}
+		if _, ok := values[0].(int); !ok || len(values) != 1 {
+			result.untranslated(source, construct, "needs a single integer value")
+			return nil
+		}
+		return &models.Condition{Field: field, Operator: operator, Value: values[0]}
+	}
+	result.untranslated(source, construct, fmt.Sprintf("operator %s has no equivalent", m.Operator))
+	return nil
+}
+
+// tetragonLiteral reads integers (decimal or 0x hex) as numbers
+func tetragonLiteral(value string) interface{} {
+	if n, err := strconv.ParseInt(value, 0, 64); err == nil {
+		return int(n)
+	}
+	return value
+}
+
+func tetragonActionKnown(action string) bool {
+	for _, a := range tetragonActions {
+		if strings.EqualFold(a.tetragon, action) {
+			return true
+		}
+	}
+	return false
+}
+
+// strongerAction returns whichever of the current rule action and a Tetragon
+// action ranks first in tetragonActions
+func strongerAction(current, action string) string {
+	for _, a := range tetragonActions {
+		switch {
+		case strings.EqualFold(a.tetragon, action):
+			return a.action
+		case a.action == current:
+			return current
+		}
+	}
+	return current
+}
@@ -0,0 +1,251 @@
+package evaluator
+
+import (
+	"admin_server/backend/internal/models"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Condition operators
+const (
+	OpEquals    = "equals"
+	OpNotEquals = "not_equals"
+	OpIn        = "in"
+	OpNotIn     = "not_in"
+	OpPrefix    = "prefix"
+	OpSuffix    = "suffix"
+	OpContains  = "contains"
+	OpRegex     = "regex"
+	OpGT        = "gt"
+	OpGTE       = "gte"
+	OpLT        = "lt"
+	OpLTE       = "lte"
+)
+
+// Event is one syscall event as the rule engine sees it, keyed by condition field
+// name (syscall_name, path, comm, ...). Syscall arguments are read from "args.N"
+// or, failing that, from index N of an "args" list.
+type Event map[string]interface{}
+
+// Lookup returns the value of field in event
+func (e Event) Lookup(field string) (interface{}, bool) {
+	if value, ok := e[field]; ok && value != nil {
+		return value, true
+	}
+	if index, ok := strings.CutPrefix(field, "args."); ok {
+		args, _ := e["args"].([]interface{})
+		n, err := strconv.Atoi(index)
+		if err == nil && n >= 0 && n < len(args) && args[n] != nil {
+			return args[n], true
+		}
+	}
+	return nil, false
+}
+
+// Match reports whether event satisfies every condition, i.e. a rule's condition
+// list as an implicit all-group. A leaf condition on a field the event lacks never
+// matches, whatever the operator, so not_equals and not_in also require the field to be
+// present. A not group only negates its child, so not over a missing field matches.
+func Match(conditions []models.Condition, event Event) (bool, error) {
+	for _, cond := range conditions {
+		ok, err := MatchCondition(cond, event)
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// MatchCondition evaluates a leaf condition or an all/any/not group against event
+func MatchCondition(cond models.Condition, event Event) (bool, error) {
+	switch cond.GroupKind() {
+	case models.ConditionNot:
+		ok, err := MatchCondition(*cond.Not, event)
+		return !ok && err == nil, err
+	case models.ConditionAll:
+		return Match(cond.All, event)
+	case models.ConditionAny:
+		for _, child := range cond.Any {
+			ok, err := MatchCondition(child, event)
+			if err != nil || ok {
+				return ok, err
+			}
+		}
+		return false, nil
+	}
+
+	actual, ok := event.Lookup(cond.Field)
+	if !ok {
+		return false, nil
+	}
+	return compare(cond.Operator, actual, cond.Value)
+}
+
+func compare(operator string, actual, expected interface{}) (bool, error) {
+	switch operator {
+	case OpEquals:
+		return Equal(actual, expected), nil
+	case OpNotEquals:
+		return !Equal(actual, expected), nil
+	case OpIn, OpNotIn:
+		items, ok := expected.([]interface{})
+		if !ok {
+			return false, fmt.Errorf("operator %q requires a list", operator)
+		}
+		found := false
+		for _, item := range items {
+			if Equal(actual, item) {
+				found = true
+				break
+			}
+		}
+		return found == (operator == OpIn), nil
+	case OpPrefix, OpSuffix, OpContains, OpRegex:
+		pattern, ok := expected.(string)
+		if !ok {
+			return false, fmt.Errorf("operator %q requires a string", operator)
+		}
+		return MatchString(operator, toString(actual), pattern)
+	case OpGT, OpGTE, OpLT, OpLTE:
+		want, ok := Number(expected)
+		if !ok {
+			return false, fmt.Errorf("operator %q requires a number", operator)
+		}
+		got, ok := Number(actual)
+		if !ok {
+			if got, ok = parseNumber(actual); !ok {
+				return false, nil
+			}
+		}
+		switch operator {
+		case OpGT:
+			return got > want, nil
+		case OpGTE:
+			return got >= want, nil
+		case OpLT:
+			return got < want, nil
+		default:
+			return got <= want, nil
+		}
+	}
+	return false, fmt.Errorf("unknown operator %q", operator)
+}
+
+// MatchString applies a string operator (prefix, suffix, contains or regex) to s.
+// Regexes are unanchored, as with regexp.MatchString.
+func MatchString(operator, s, pattern string) (bool, error) {
+	switch operator {
+	case OpPrefix:
+		return strings.HasPrefix(s, pattern), nil
+	case OpSuffix:
+		return strings.HasSuffix(s, pattern), nil
+	case OpContains:
+		return strings.Contains(s, pattern), nil
+	case OpRegex:
+		re, err := compileRegex(pattern)
+		if err != nil {
+			return false, err
+		}
+		return re.MatchString(s), nil
+	}
+	return false, fmt.Errorf("operator %q is not a string operator", operator)
+}
+
+// Equal compares an event value with a condition value: numerically when both
+// are numbers (or numeric strings against a number), otherwise as strings
+func Equal(actual, expected interface{}) bool {
+	if want, ok := Number(expected); ok {
+		got, ok := Number(actual)
+		if !ok {
+			got, ok = parseNumber(actual)
+		}
+		return ok && got == want
+	}
+	return toString(actual) == toString(expected)
+}
+
+// Number accepts the number types produced by encoding/json (including json.Number)
+// and yaml.v3
+func Number(value interface{}) (float64, bool) {
+	switch n := value.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
+
+// parseNumber reads a numeric string, e.g. a syscall argument recorded as "0x1"
+// or "42"
+func parseNumber(value interface{}) (float64, bool) {
+	s, ok := value.(string)
+	if !ok {
+		return 0, false
+	}
+	if n, err := strconv.ParseInt(s, 0, 64); err == nil {
+		return float64(n), true
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	return f, err == nil
+}
+
+func toString(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case nil:
+		return ""
+	}
+	if n, ok := Number(value); ok {
+		return strconv.FormatFloat(n, 'f', -1, 64)
+	}
+	return fmt.Sprint(value)
+}
+
+// regexCacheSize bounds the compiled regex cache, whose patterns come from rule sets
+// and simulate/test payloads
+const regexCacheSize = 256
+
+var regexCache = struct {
+	sync.Mutex
+	patterns map[string]*regexp.Regexp
+}{patterns: map[string]*regexp.Regexp{}}
+
+func compileRegex(pattern string) (*regexp.Regexp, error) {
+	regexCache.Lock()
+	re, ok := regexCache.patterns[pattern]
+	regexCache.Unlock()
+	if ok {
+		return re, nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid regex %q: %w", pattern, err)
+	}
+
+	regexCache.Lock()
+	// 가득 차면 통째로 비움: 룰셋 하나의 패턴은 다시 채워짐
+	if len(regexCache.patterns) >= regexCacheSize {
+		clear(regexCache.patterns)
+	}
+	regexCache.patterns[pattern] = re
+	regexCache.Unlock()
+	return re, nil
+}
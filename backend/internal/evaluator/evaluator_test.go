@@ -0,0 +1,195 @@
+package evaluator
+
+import (
+	"admin_server/backend/internal/models"
+	"encoding/json"
+	"fmt"
+	"testing"
+)
+
+func leaf(field, operator string, value interface{}) models.Condition {
+	return models.Condition{Field: field, Operator: operator, Value: value}
+}
+
+func TestMatchConditionOperators(t *testing.T) {
+	event := Event{
+		"syscall_name": "openat",
+		"path":         "/etc/shadow",
+		"uid":          json.Number("0"),
+		"flags":        "0x241",
+		"args":         []interface{}{"/bin/sh", float64(2)},
+	}
+	list := func(items ...interface{}) []interface{} { return items }
+
+	tests := []struct {
+		name string
+		cond models.Condition
+		want bool
+	}{
+		{"equals", leaf("syscall_name", OpEquals, "openat"), true},
+		{"equals other", leaf("syscall_name", OpEquals, "execve"), false},
+		{"not_equals", leaf("syscall_name", OpNotEquals, "execve"), true},
+		{"not_equals same", leaf("syscall_name", OpNotEquals, "openat"), false},
+		{"in", leaf("syscall_name", OpIn, list("execve", "openat")), true},
+		{"in absent", leaf("syscall_name", OpIn, list("execve")), false},
+		{"not_in", leaf("syscall_name", OpNotIn, list("execve")), true},
+		{"not_in present", leaf("syscall_name", OpNotIn, list("openat")), false},
+		{"prefix", leaf("path", OpPrefix, "/etc/"), true},
+		{"prefix other", leaf("path", OpPrefix, "/var/"), false},
+		{"suffix", leaf("path", OpSuffix, "shadow"), true},
+		{"suffix other", leaf("path", OpSuffix, "passwd"), false},
+		{"contains", leaf("path", OpContains, "sha"), true},
+		{"contains other", leaf("path", OpContains, "pass"), false},
+		{"regex is unanchored", leaf("path", OpRegex, `etc/(shadow|gshadow)`), true},
+		{"regex anchored", leaf("path", OpRegex, `^shadow$`), false},
+		{"gt", leaf("uid", OpGT, -1), true},
+		{"gt equal", leaf("uid", OpGT, 0), false},
+		{"gte equal", leaf("uid", OpGTE, 0), true},
+		{"lt", leaf("uid", OpLT, 1), true},
+		{"lt equal", leaf("uid", OpLT, 0), false},
+		{"lte equal", leaf("uid", OpLTE, 0), true},
+		{"args index", leaf("args.0", OpEquals, "/bin/sh"), true},
+		{"args index number", leaf("args.1", OpGTE, 2), true},
+		{"args out of range", leaf("args.5", OpNotEquals, "x"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := MatchCondition(tt.cond, event)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Fatalf("MatchCondition(%s %s %v) = %v, want %v", tt.cond.Field, tt.cond.Operator, tt.cond.Value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatchConditionMissingField(t *testing.T) {
+	event := Event{"syscall_name": "execve", "comm": nil}
+
+	// 없는 필드(값이 nil인 필드 포함)에 대한 잎 조건은 연산자와 관계없이 일치하지 않음
+	for _, operator := range []string{OpEquals, OpNotEquals, OpIn, OpNotIn, OpPrefix, OpSuffix, OpContains, OpRegex, OpGT, OpGTE, OpLT, OpLTE} {
+		var value interface{} = "x"
+		switch operator {
+		case OpIn, OpNotIn:
+			value = []interface{}{"x"}
+		case OpGT, OpGTE, OpLT, OpLTE:
+			value = 1
+		}
+		for _, field := range []string{"path", "comm", "args.0"} {
+			if got, err := MatchCondition(leaf(field, operator, value), event); got || err != nil {
+				t.Fatalf("%s %s on a missing field = %v, %v; want false", field, operator, got, err)
+			}
+		}
+	}
+
+	// not은 자식 결과만 뒤집음: not_equals와 달리 없는 필드에서 일치함
+	notEquals := leaf("path", OpNotEquals, "/etc/shadow")
+	notOfEquals := models.Condition{Not: &models.Condition{Field: "path", Operator: OpEquals, Value: "/etc/shadow"}}
+	if got, _ := MatchCondition(notEquals, event); got {
+		t.Fatal("not_equals matched a missing field")
+	}
+	if got, _ := MatchCondition(notOfEquals, event); !got {
+		t.Fatal("not over a condition on a missing field did not match")
+	}
+}
+
+func TestMatchConditionNumericCoercion(t *testing.T) {
+	tests := []struct {
+		name     string
+		actual   interface{}
+		operator string
+		expected interface{}
+		want     bool
+	}{
+		{"decimal string equals number", "42", OpEquals, 42, true},
+		{"hex string equals number", "0x1", OpEquals, 1, true},
+		{"octal string equals number", "0755", OpEquals, 493, true},
+		{"float string equals number", "1.5", OpEquals, 1.5, true},
+		{"json.Number equals int", json.Number("42"), OpEquals, 42, true},
+		{"int64 equals float", int64(7), OpEquals, 7.0, true},
+		{"number equals numeric string is a string comparison", 42, OpEquals, "42", true},
+		{"non-numeric string never equals a number", "abc", OpEquals, 0, false},
+		{"non-numeric string not_equals a number", "abc", OpNotEquals, 0, true},
+		{"hex string gt", "0x10", OpGT, 15, true},
+		{"non-numeric string gt", "abc", OpGT, 0, false},
+		{"numeric string in list", "2", OpIn, []interface{}{1, 2}, true},
+		{"large json.Number keeps precision", json.Number("4294967296"), OpEquals, uint64(4294967296), true},
+		{"number rendered for string operator", 1.5, OpPrefix, "1.", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := MatchCondition(leaf("value", tt.operator, tt.expected), Event{"value": tt.actual})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Fatalf("%v %s %v = %v, want %v", tt.actual, tt.operator, tt.expected, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatchGroups(t *testing.T) {
+	event := Event{"syscall_name": "execve", "comm": "bash"}
+	execve := leaf("syscall_name", OpEquals, "execve")
+	sh := leaf("comm", OpEquals, "sh")
+	bash := leaf("comm", OpEquals, "bash")
+
+	tests := []struct {
+		name       string
+		conditions []models.Condition
+		want       bool
+	}{
+		{"empty list", nil, true},
+		{"implicit all", []models.Condition{execve, bash}, true},
+		{"implicit all with a miss", []models.Condition{execve, sh}, false},
+		{"any", []models.Condition{{Any: []models.Condition{sh, bash}}}, true},
+		{"any without a match", []models.Condition{{Any: []models.Condition{sh}}}, false},
+		{"all", []models.Condition{{All: []models.Condition{execve, bash}}}, true},
+		{"not", []models.Condition{execve, {Not: &sh}}, true},
+		{"not of a match", []models.Condition{{Not: &bash}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Match(tt.conditions, event)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Fatalf("Match = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatchConditionErrors(t *testing.T) {
+	event := Event{"comm": "bash"}
+	for _, cond := range []models.Condition{
+		leaf("comm", "like", "bash"),
+		leaf("comm", OpIn, "bash"),
+		leaf("comm", OpPrefix, 1),
+		leaf("comm", OpGT, "ten"),
+		leaf("comm", OpRegex, "("),
+		{Not: &models.Condition{Field: "comm", Operator: OpRegex, Value: "("}},
+	} {
+		if got, err := MatchCondition(cond, event); err == nil || got {
+			t.Fatalf("%+v = %v, %v; want an error", cond, got, err)
+		}
+	}
+}
+
+func TestRegexCacheIsBounded(t *testing.T) {
+	for i := 0; i < 3*regexCacheSize; i++ {
+		if _, err := MatchString(OpRegex, "x", fmt.Sprintf("^p%d$", i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	regexCache.Lock()
+	size := len(regexCache.patterns)
+	regexCache.Unlock()
+	if size > regexCacheSize {
+		t.Fatalf("regex cache holds %d patterns, want at most %d", size, regexCacheSize)
+	}
+}
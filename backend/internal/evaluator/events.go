@@ -0,0 +1,49 @@
+package evaluator
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// maxEventLine bounds a single JSONL line
+const maxEventLine = 1 << 20
+
+// ErrTooManyEvents is returned when a JSONL stream holds more events than allowed
+var ErrTooManyEvents = errors.New("too many events")
+
+// ReadEvents parses one JSON object per line, skipping blank lines. Numbers are kept
+// as json.Number so large syscall arguments keep their precision. At most max
+// events are read (0 means no limit).
+func ReadEvents(r io.Reader, max int) ([]Event, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxEventLine)
+
+	var events []Event
+	line := 0
+	for scanner.Scan() {
+		line++
+		raw := bytes.TrimSpace(scanner.Bytes())
+		if len(raw) == 0 {
+			continue
+		}
+		if max > 0 && len(events) >= max {
+			return nil, fmt.Errorf("%w: limit is %d", ErrTooManyEvents, max)
+		}
+
+		decoder := json.NewDecoder(bytes.NewReader(raw))
+		decoder.UseNumber()
+		var event Event
+		if err := decoder.Decode(&event); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		events = append(events, event)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("line %d: %w", line+1, err)
+	}
+	return events, nil
+}
@@ -0,0 +1,42 @@
+package evaluator
+
+import "admin_server/backend/internal/models"
+
+// RunTests evaluates every test embedded in ruleSet against its rule, including the
+// tests of disabled rules. A test with an unknown expectation or a condition that
+// cannot be evaluated fails with Error set.
+func RunTests(ruleSet *models.RuleSet) *models.RuleTestReport {
+	report := &models.RuleTestReport{
+		RulesetVersion: ruleSet.RulesetVersion,
+		Results:        make([]models.RuleTestResult, 0),
+	}
+	for _, rule := range ruleSet.Rules {
+		for i, test := range rule.Tests {
+			result := models.RuleTestResult{
+				RuleID: rule.RuleID,
+				Index:  i,
+				Name:   test.Name,
+				Expect: test.Expect,
+			}
+			matched, err := Match(rule.Conditions, Event(test.Event))
+			switch {
+			case test.Expect != models.RuleTestMatch && test.Expect != models.RuleTestNoMatch:
+				result.Error = "expect must be " + models.RuleTestMatch + " or " + models.RuleTestNoMatch
+			case err != nil:
+				result.Error = err.Error()
+			default:
+				result.Matched = matched
+				result.Passed = matched == (test.Expect == models.RuleTestMatch)
+			}
+
+			report.Total++
+			if result.Passed {
+				report.Passed++
+			} else {
+				report.Failed++
+			}
+			report.Results = append(report.Results, result)
+		}
+	}
+	return report
+}
@@ -1,15 +1,24 @@
 package handlers
 
 import (
+	"admin_server/backend/internal/auth"
 	"admin_server/backend/internal/models"
 	"admin_server/backend/internal/services"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
+	"github.com/gin-contrib/sse"
 	"github.com/gin-gonic/gin"
 )
 
+// streamHeartbeat keeps idle SSE connections open through proxies
+const streamHeartbeat = 15 * time.Second
+
 type AlertHandler struct {
 	service *services.AlertService
 }
@@ -30,15 +39,18 @@ func (h *AlertHandler) GetAlerts(c *gin.Context) {
 		}
 	}
 
-	var since *time.Time
-	if sinceStr := c.Query("since"); sinceStr != "" {
-		if parsedTime, err := time.Parse(time.RFC3339, sinceStr); err == nil {
-			since = &parsedTime
-		}
+	filter, err := parseAlertFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
 	}
 
-	response, err := h.service.GetAlerts(limit, since)
+	response, err := h.service.GetAlerts(filter, limit, c.Query("cursor"))
 	if err != nil {
+		if errors.Is(err, services.ErrInvalidCursor) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -46,6 +58,50 @@ func (h *AlertHandler) GetAlerts(c *gin.Context) {
 	c.JSON(http.StatusOK, response)
 }
 
+// parseAlertFilter reads the filters shared by GET /alerts and /alerts/stream:
+// since, until (RFC3339), severity and state (repeatable or comma-separated), rule_id, namespace, pod_name, q
+func parseAlertFilter(c *gin.Context) (services.AlertFilter, error) {
+	filter := services.AlertFilter{
+		RuleID:    c.Query("rule_id"),
+		Namespace: c.Query("namespace"),
+		PodName:   c.Query("pod_name"),
+		Query:     c.Query("q"),
+	}
+
+	if sinceStr := c.Query("since"); sinceStr != "" {
+		parsedTime, err := time.Parse(time.RFC3339, sinceStr)
+		if err != nil {
+			return filter, fmt.Errorf("invalid since: %w", err)
+		}
+		filter.Since = &parsedTime
+	}
+	if untilStr := c.Query("until"); untilStr != "" {
+		parsedTime, err := time.Parse(time.RFC3339, untilStr)
+		if err != nil {
+			return filter, fmt.Errorf("invalid until: %w", err)
+		}
+		filter.Until = &parsedTime
+	}
+
+	filter.Severities = queryList(c, "severity")
+	filter.States = queryList(c, "state")
+
+	return filter, nil
+}
+
+// queryList collects a query parameter given repeatedly and/or comma-separated
+func queryList(c *gin.Context, key string) []string {
+	var values []string
+	for _, value := range c.QueryArray(key) {
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				values = append(values, item)
+			}
+		}
+	}
+	return values
+}
+
 // ReceiveWebhook handles POST /api/v1/alerts/webhook
 func (h *AlertHandler) ReceiveWebhook(c *gin.Context) {
 	var alert models.WebhookAlert
@@ -55,10 +111,146 @@ func (h *AlertHandler) ReceiveWebhook(c *gin.Context) {
 	}
 
 	if err := h.service.ReceiveWebhook(&alert); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondAlertError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"status": "received"})
 }
 
+// GetAlert handles GET /api/v1/alerts/:id
+func (h *AlertHandler) GetAlert(c *gin.Context) {
+	alert, err := h.service.GetAlert(c.Param("id"))
+	if err != nil {
+		respondAlertError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, alert)
+}
+
+// UpdateAlert handles PATCH /api/v1/alerts/:id
+func (h *AlertHandler) UpdateAlert(c *gin.Context) {
+	var req models.UpdateAlertRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if identity := auth.IdentityFrom(c); identity != nil {
+		req.Actor = identity.Subject
+	}
+
+	alert, err := h.service.UpdateAlert(c.Param("id"), &req)
+	if err != nil {
+		respondAlertError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, alert)
+}
+
+// AddAlertComment handles POST /api/v1/alerts/:id/comments
+func (h *AlertHandler) AddAlertComment(c *gin.Context) {
+	var req models.AddAlertCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if identity := auth.IdentityFrom(c); identity != nil {
+		req.Author = identity.Subject
+	}
+
+	alert, err := h.service.AddAlertComment(c.Param("id"), &req)
+	if err != nil {
+		respondAlertError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, alert)
+}
+
+// respondAlertError maps AlertService errors to HTTP status codes
+func respondAlertError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrAlertNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrInvalidAlertUpdate), errors.Is(err, services.ErrInvalidCursor), errors.Is(err, services.ErrInvalidAlert):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// StreamAlerts handles GET /api/v1/alerts/stream (Server-Sent Events).
+// Accepts the same filters as GetAlerts. Stored alerts after `since` (or after
+// Last-Event-ID) are replayed first, then live alerts follow.
+func (h *AlertHandler) StreamAlerts(c *gin.Context) {
+	limit := 0 // replay everything after since by default
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if parsedLimit, err := strconv.Atoi(limitStr); err == nil && parsedLimit > 0 {
+			limit = parsedLimit
+		}
+	}
+
+	filter, err := parseAlertFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// EventSource는 재연결 시 Last-Event-ID 헤더를 보냄 (쿼리는 수동 재개용)
+	lastEventID := c.GetHeader("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = c.Query("last_event_id")
+	}
+
+	replay, sub, err := h.service.SubscribeAlerts(filter, limit, lastEventID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	defer sub.Close()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no") // nginx 프록시 버퍼링 비활성화
+
+	replayed := make(map[string]struct{}, len(replay))
+	for _, alert := range replay {
+		replayed[alert.AlertID] = struct{}{}
+		writeAlertEvent(c, alert)
+	}
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-c.Request.Context().Done():
+			return false
+		case alert, ok := <-sub.C:
+			if !ok {
+				// Dropped for falling behind; the client reconnects with Last-Event-ID
+				return false
+			}
+			if _, dup := replayed[alert.AlertID]; dup {
+				delete(replayed, alert.AlertID)
+				return true
+			}
+			writeAlertEvent(c, alert)
+		case <-heartbeat.C:
+			io.WriteString(w, ": keepalive\n\n")
+		}
+		return true
+	})
+}
+
+func writeAlertEvent(c *gin.Context, alert models.Alert) {
+	c.Render(-1, sse.Event{
+		Id:    services.AlertEventID(alert),
+		Event: "alert",
+		Data:  alert,
+	})
+}
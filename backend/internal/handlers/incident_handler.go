@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"admin_server/backend/internal/services"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type IncidentHandler struct {
+	service      *services.IncidentService
+	alertService *services.AlertService
+}
+
+func NewIncidentHandler(service *services.IncidentService, alertService *services.AlertService) *IncidentHandler {
+	return &IncidentHandler{
+		service:      service,
+		alertService: alertService,
+	}
+}
+
+// GetIncidents handles GET /api/v1/incidents
+func (h *IncidentHandler) GetIncidents(c *gin.Context) {
+	limit := 50 // default
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if parsedLimit, err := strconv.Atoi(limitStr); err == nil && parsedLimit > 0 {
+			limit = parsedLimit
+		}
+	}
+
+	var since *time.Time
+	if sinceStr := c.Query("since"); sinceStr != "" {
+		parsedTime, err := time.Parse(time.RFC3339, sinceStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid since: " + err.Error()})
+			return
+		}
+		since = &parsedTime
+	}
+
+	response, err := h.service.GetIncidents(limit, since, c.Query("rule_id"), c.Query("namespace"), c.Query("pod_name"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetIncident handles GET /api/v1/incidents/:id
+func (h *IncidentHandler) GetIncident(c *gin.Context) {
+	incident, err := h.service.GetIncident(c.Param("id"))
+	if err != nil {
+		respondIncidentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, incident)
+}
+
+// GetIncidentAlerts handles GET /api/v1/incidents/:id/alerts (paged like GET /alerts)
+func (h *IncidentHandler) GetIncidentAlerts(c *gin.Context) {
+	incident, err := h.service.GetIncident(c.Param("id"))
+	if err != nil {
+		respondIncidentError(c, err)
+		return
+	}
+
+	limit := 50 // default
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if parsedLimit, err := strconv.Atoi(limitStr); err == nil && parsedLimit > 0 {
+			limit = parsedLimit
+		}
+	}
+
+	filter := services.AlertFilter{IncidentID: incident.IncidentID}
+	// 인시던트 기간으로 조회 범위를 좁힘 (since는 exclusive이므로 1초 앞에서 시작)
+	if firstSeen, err := time.Parse(time.RFC3339, incident.FirstSeen); err == nil {
+		since := firstSeen.Add(-time.Second)
+		filter.Since = &since
+	}
+
+	response, err := h.alertService.GetAlerts(filter, limit, c.Query("cursor"))
+	if err != nil {
+		respondAlertError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func respondIncidentError(c *gin.Context, err error) {
+	if errors.Is(err, services.ErrIncidentNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+}
@@ -1,49 +1,631 @@
 package handlers
 
 import (
+	"admin_server/backend/internal/auth"
 	"admin_server/backend/internal/models"
 	"admin_server/backend/internal/services"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/gin-contrib/sse"
 	"github.com/gin-gonic/gin"
 )
 
 type RuleHandler struct {
-	service *services.RuleService
+	service      *services.RuleService
+	alertService *services.AlertService
 }
 
-func NewRuleHandler(service *services.RuleService) *RuleHandler {
+func NewRuleHandler(service *services.RuleService, alertService *services.AlertService) *RuleHandler {
 	return &RuleHandler{
-		service: service,
+		service:      service,
+		alertService: alertService,
 	}
 }
 
-// GetRules handles GET /api/v1/rules
+// ListRulesets handles GET /api/v1/rulesets
+func (h *RuleHandler) ListRulesets(c *gin.Context) {
+	c.JSON(http.StatusOK, h.service.ListRulesets())
+}
+
+// GetRuleset handles GET /api/v1/rulesets/:name
+func (h *RuleHandler) GetRuleset(c *gin.Context) {
+	service, ok := h.ruleset(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, service.Status())
+}
+
+// ruleset returns the RuleService for the :name path parameter, or the default ruleset's
+// for the unscoped /api/v1/rules routes. An unknown name is answered with 404.
+func (h *RuleHandler) ruleset(c *gin.Context) (*services.RuleService, bool) {
+	name := c.Param("name")
+	if name == "" {
+		return h.service, true
+	}
+	service, err := h.service.Ruleset(name)
+	if err != nil {
+		respondRuleError(c, err)
+		return nil, false
+	}
+	return service, true
+}
+
+// GetRules handles GET /api/v1/rules. The ConfigMap resourceVersion is returned as the ETag.
+// A filtered response carries no ETag, so a partial RuleSet cannot be PUT back by mistake.
 func (h *RuleHandler) GetRules(c *gin.Context) {
-	rules, err := h.service.GetRules()
+	service, ok := h.ruleset(c)
+	if !ok {
+		return
+	}
+	filter, err := parseRuleFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	rules, resourceVersion, err := service.GetRules()
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, rules)
+	if filter.IsZero() {
+		c.Header("ETag", formatETag(resourceVersion))
+	}
+	c.JSON(http.StatusOK, services.FilterRules(rules, filter))
+}
+
+// parseRuleFilter reads the GET /rules filters: enabled (true/false), owner, and
+// severity, tag and action (repeatable or comma-separated)
+func parseRuleFilter(c *gin.Context) (services.RuleFilter, error) {
+	filter := services.RuleFilter{
+		Severities: queryList(c, "severity"),
+		Tags:       queryList(c, "tag"),
+		Actions:    queryList(c, "action"),
+		Owner:      c.Query("owner"),
+	}
+	if enabledStr := c.Query("enabled"); enabledStr != "" {
+		enabled, err := strconv.ParseBool(enabledStr)
+		if err != nil {
+			return filter, fmt.Errorf("invalid enabled: %w", err)
+		}
+		filter.Enabled = &enabled
+	}
+	return filter, nil
 }
 
-// UpdateRules handles PUT /api/v1/rules
+// UpdateRules handles PUT /api/v1/rules. If-Match must carry the ETag from GET /api/v1/rules.
 func (h *RuleHandler) UpdateRules(c *gin.Context) {
+	service, ok := h.ruleset(c)
+	if !ok {
+		return
+	}
 	var ruleSet models.RuleSet
 	if err := c.ShouldBindJSON(&ruleSet); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	// Validate rules
-	if err := h.service.ValidateRules(&ruleSet); err != nil {
+	// Validate rules (?strict=true rejects syscalls outside cluster_callable_syscalls)
+	warnings, err := service.ValidateRules(&ruleSet, c.Query("strict") == "true")
+	if err != nil {
+		respondRuleError(c, err)
+		return
+	}
+
+	response, err := service.UpdateRules(&ruleSet, parseIfMatch(c.GetHeader("If-Match")), requestAuthor(c))
+	if err != nil {
+		respondRuleError(c, err)
+		return
+	}
+
+	response.Warnings = warnings
+
+	c.Header("ETag", formatETag(response.ResourceVersion))
+	c.JSON(http.StatusOK, response)
+}
+
+// GetRule handles GET /api/v1/rules/:rule_id. The ETag is the ruleset's, as every
+// per-rule edit rewrites the same ConfigMap.
+func (h *RuleHandler) GetRule(c *gin.Context) {
+	service, ok := h.ruleset(c)
+	if !ok {
+		return
+	}
+	rule, resourceVersion, err := service.GetRule(c.Param("rule_id"))
+	if err != nil {
+		respondRuleError(c, err)
+		return
+	}
+
+	c.Header("ETag", formatETag(resourceVersion))
+	c.JSON(http.StatusOK, rule)
+}
+
+// CreateRule handles POST /api/v1/rules/:rule_id. If-Match is optional for per-rule
+// edits; without it a concurrent write is retried against the new ruleset.
+func (h *RuleHandler) CreateRule(c *gin.Context) {
+	service, ok := h.ruleset(c)
+	if !ok {
+		return
+	}
+	var rule models.Rule
+	if err := c.ShouldBindJSON(&rule); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	response, err := h.service.UpdateRules(&ruleSet)
+	response, err := service.CreateRule(c.Param("rule_id"), rule, parseIfMatch(c.GetHeader("If-Match")), requestAuthor(c), c.Query("strict") == "true")
+	if err != nil {
+		respondRuleError(c, err)
+		return
+	}
+
+	c.Header("ETag", formatETag(response.ResourceVersion))
+	c.JSON(http.StatusCreated, response)
+}
+
+// ReplaceRule handles PUT /api/v1/rules/:rule_id
+func (h *RuleHandler) ReplaceRule(c *gin.Context) {
+	service, ok := h.ruleset(c)
+	if !ok {
+		return
+	}
+	var rule models.Rule
+	if err := c.ShouldBindJSON(&rule); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	response, err := service.ReplaceRule(c.Param("rule_id"), rule, parseIfMatch(c.GetHeader("If-Match")), requestAuthor(c), c.Query("strict") == "true")
+	if err != nil {
+		respondRuleError(c, err)
+		return
+	}
+
+	c.Header("ETag", formatETag(response.ResourceVersion))
+	c.JSON(http.StatusOK, response)
+}
+
+// DeleteRule handles DELETE /api/v1/rules/:rule_id
+func (h *RuleHandler) DeleteRule(c *gin.Context) {
+	service, ok := h.ruleset(c)
+	if !ok {
+		return
+	}
+	response, err := service.DeleteRule(c.Param("rule_id"), parseIfMatch(c.GetHeader("If-Match")), requestAuthor(c))
+	if err != nil {
+		respondRuleError(c, err)
+		return
+	}
+
+	c.Header("ETag", formatETag(response.ResourceVersion))
+	c.JSON(http.StatusOK, response)
+}
+
+// PatchRules handles PATCH /api/v1/rules. The Content-Type selects JSON Patch
+// (application/json-patch+json) or JSON merge patch (application/merge-patch+json).
+func (h *RuleHandler) PatchRules(c *gin.Context) {
+	service, ok := h.ruleset(c)
+	if !ok {
+		return
+	}
+	patch, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	response, err := service.PatchRules(c.ContentType(), patch, parseIfMatch(c.GetHeader("If-Match")), requestAuthor(c), c.Query("strict") == "true")
+	if err != nil {
+		respondRuleError(c, err)
+		return
+	}
+
+	c.Header("ETag", formatETag(response.ResourceVersion))
+	c.JSON(http.StatusOK, response)
+}
+
+// ValidateRules handles POST /api/v1/rules/validate, a dry run of PUT /api/v1/rules
+// that reports problems and warnings without writing anything
+func (h *RuleHandler) ValidateRules(c *gin.Context) {
+	service, ok := h.ruleset(c)
+	if !ok {
+		return
+	}
+	var ruleSet models.RuleSet
+	if err := c.ShouldBindJSON(&ruleSet); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	warnings, err := service.ValidateRules(&ruleSet, c.Query("strict") == "true")
+	var invalid *services.RuleValidationError
+	if err != nil && !errors.As(err, &invalid) {
+		respondRuleError(c, err)
+		return
+	}
+
+	response := models.ValidateRulesResponse{
+		Valid:    err == nil,
+		Problems: []models.ValidationProblem{},
+		Warnings: warnings,
+	}
+	if invalid != nil {
+		response.Problems = invalid.Problems
+	}
+	if response.Warnings == nil {
+		response.Warnings = []models.ValidationProblem{}
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// SimulateRules handles POST /api/v1/rules/simulate. The candidate RuleSet and the
+// events come either as JSON ({"ruleset", "events"}) or as multipart form data with a
+// "ruleset" field (JSON) and an "events" file (JSONL). With ?source=alerts the events
+// are stored alerts' syscall_log instead, selected with the GET /alerts filters and
+// ?limit (default 1000). Without a ruleset the live rules are simulated.
+func (h *RuleHandler) SimulateRules(c *gin.Context) {
+	service, ok := h.ruleset(c)
+	if !ok {
+		return
+	}
+	var ruleSet *models.RuleSet
+	var events []services.SimulationEvent
+
+	if c.ContentType() == "multipart/form-data" {
+		if raw := c.PostForm("ruleset"); raw != "" {
+			ruleSet = &models.RuleSet{}
+			if err := json.Unmarshal([]byte(raw), ruleSet); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ruleset: " + err.Error()})
+				return
+			}
+		}
+		if c.Query("source") != services.SimulationSourceAlerts {
+			fileHeader, err := c.FormFile("events")
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "events file is required: " + err.Error()})
+				return
+			}
+			file, err := fileHeader.Open()
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			defer file.Close()
+			if events, err = service.ReadSimulationEvents(file); err != nil {
+				respondRuleError(c, err)
+				return
+			}
+		}
+	} else if c.Request.ContentLength != 0 {
+		var req models.SimulateRulesRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		ruleSet = req.RuleSet
+		for _, event := range req.Events {
+			events = append(events, services.SimulationEvent{Event: event})
+		}
+	}
+
+	source := services.SimulationSourceUpload
+	if c.Query("source") == services.SimulationSourceAlerts {
+		source = services.SimulationSourceAlerts
+		filter, err := parseAlertFilter(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		limit := 1000
+		if limitStr := c.Query("limit"); limitStr != "" {
+			if parsedLimit, err := strconv.Atoi(limitStr); err == nil && parsedLimit > 0 {
+				limit = parsedLimit
+			}
+		}
+		alerts, err := h.alertService.GetAlerts(filter, limit, "")
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		events = services.AlertSimulationEvents(alerts.Alerts)
+	}
+
+	samples := 5
+	if samplesStr := c.Query("samples"); samplesStr != "" {
+		if parsed, err := strconv.Atoi(samplesStr); err == nil {
+			samples = parsed
+		}
+	}
+
+	response, err := service.SimulateRules(ruleSet, events, source, samples)
+	if err != nil {
+		respondRuleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// TestRules handles POST /api/v1/rules/test: it runs the tests embedded in the posted
+// RuleSet, or in the live rules when the body is empty
+func (h *RuleHandler) TestRules(c *gin.Context) {
+	service, ok := h.ruleset(c)
+	if !ok {
+		return
+	}
+	var ruleSet *models.RuleSet
+	if c.Request.ContentLength != 0 {
+		ruleSet = &models.RuleSet{}
+		if err := c.ShouldBindJSON(ruleSet); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	report, err := service.TestRules(ruleSet)
+	if err != nil {
+		respondRuleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// ExportRules handles GET /api/v1/rules/export?format=yaml|json as a file download.
+// The ConfigMap resourceVersion is returned as the ETag.
+func (h *RuleHandler) ExportRules(c *gin.Context) {
+	service, ok := h.ruleset(c)
+	if !ok {
+		return
+	}
+	format := c.Query("format")
+	if format == "" {
+		format = "yaml"
+	}
+	data, ruleSet, resourceVersion, err := service.ExportRules(format)
+	if err != nil {
+		respondRuleError(c, err)
+		return
+	}
+
+	contentType := "application/yaml"
+	if format == "json" {
+		contentType = "application/json"
+	}
+	c.Header("ETag", formatETag(resourceVersion))
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="rule-%s.%s"`, ruleSet.RulesetVersion, format))
+	c.Data(http.StatusOK, contentType, data)
+}
+
+// ImportRules handles POST /api/v1/rules/import, a multipart upload with a "file" and
+// optional "format" (yaml, json, falco or tetragon; detected when empty), "mode"
+// (merge or replace) and "dry_run" fields. If-Match is optional, as for per-rule edits.
+func (h *RuleHandler) ImportRules(c *gin.Context) {
+	service, ok := h.ruleset(c)
+	if !ok {
+		return
+	}
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "file is required: " + err.Error()})
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	defer file.Close()
+	data, err := services.ReadImportFile(file)
+	if err != nil {
+		respondRuleError(c, err)
+		return
+	}
+
+	dryRun := c.PostForm("dry_run") == "true" || c.Query("dry_run") == "true"
+	response, err := service.ImportRules(data, c.PostForm("format"), c.PostForm("mode"), dryRun,
+		parseIfMatch(c.GetHeader("If-Match")), requestAuthor(c), c.Query("strict") == "true")
+	if err != nil {
+		respondRuleError(c, err)
+		return
+	}
+
+	if response.Result != nil {
+		c.Header("ETag", formatETag(response.Result.ResourceVersion))
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// GetRuleStatus handles GET /api/v1/rules/status (live vs. candidate ruleset)
+func (h *RuleHandler) GetRuleStatus(c *gin.Context) {
+	service, ok := h.ruleset(c)
+	if !ok {
+		return
+	}
+	status, err := service.GetRuleStatus()
+	if err != nil {
+		respondRuleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, status)
+}
+
+// GetCandidateRules handles GET /api/v1/rules/candidate. The candidate ConfigMap
+// resourceVersion is returned as the ETag.
+func (h *RuleHandler) GetCandidateRules(c *gin.Context) {
+	service, ok := h.ruleset(c)
+	if !ok {
+		return
+	}
+	rules, resourceVersion, err := service.GetCandidateRules()
+	if err != nil {
+		respondRuleError(c, err)
+		return
+	}
+
+	c.Header("ETag", formatETag(resourceVersion))
+	c.JSON(http.StatusOK, rules)
+}
+
+// StageRules handles PUT /api/v1/rules/candidate. It validates like PUT /api/v1/rules
+// but writes to the candidate ConfigMap; If-Match is optional.
+func (h *RuleHandler) StageRules(c *gin.Context) {
+	service, ok := h.ruleset(c)
+	if !ok {
+		return
+	}
+	var ruleSet models.RuleSet
+	if err := c.ShouldBindJSON(&ruleSet); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	warnings, err := service.ValidateRules(&ruleSet, c.Query("strict") == "true")
+	if err != nil {
+		respondRuleError(c, err)
+		return
+	}
+
+	response, err := service.StageRules(&ruleSet, parseIfMatch(c.GetHeader("If-Match")), requestAuthor(c))
+	if err != nil {
+		respondRuleError(c, err)
+		return
+	}
+	response.Warnings = warnings
+
+	c.Header("ETag", formatETag(response.ResourceVersion))
+	c.JSON(http.StatusOK, response)
+}
+
+// PromoteCandidate handles POST /api/v1/rules/promote. If-Match optionally carries
+// the live ruleset ETag the promotion is based on.
+func (h *RuleHandler) PromoteCandidate(c *gin.Context) {
+	service, ok := h.ruleset(c)
+	if !ok {
+		return
+	}
+	expectedVersion := parseIfMatch(c.GetHeader("If-Match"))
+	if expectedVersion == "" {
+		expectedVersion = services.AnyResourceVersion
+	}
+
+	response, err := service.PromoteCandidate(expectedVersion, requestAuthor(c))
+	if err != nil {
+		respondRuleError(c, err)
+		return
+	}
+
+	c.Header("ETag", formatETag(response.ResourceVersion))
+	c.JSON(http.StatusOK, response)
+}
+
+// GetEngineStatuses handles GET /api/v1/rules/engines
+func (h *RuleHandler) GetEngineStatuses(c *gin.Context) {
+	service, ok := h.ruleset(c)
+	if !ok {
+		return
+	}
+	response, err := service.GetEngineStatuses()
+	if err != nil {
+		respondRuleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// ReportEngineStatus handles POST /api/v1/rules/engines/report, called by rule engine
+// instances after loading a ruleset
+func (h *RuleHandler) ReportEngineStatus(c *gin.Context) {
+	service, ok := h.ruleset(c)
+	if !ok {
+		return
+	}
+	var report models.EngineReport
+	if err := c.ShouldBindJSON(&report); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := service.ReportEngineStatus(&report); err != nil {
+		respondRuleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "success"})
+}
+
+// StreamRuleEvents handles GET /api/v1/rules/events (Server-Sent Events). Every change
+// to the rule ConfigMap is pushed, including edits made outside this server. The event
+// id is the ConfigMap resourceVersion; Last-Event-ID replays recent missed events.
+func (h *RuleHandler) StreamRuleEvents(c *gin.Context) {
+	service, ok := h.ruleset(c)
+	if !ok {
+		return
+	}
+	lastEventID := c.GetHeader("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = c.Query("last_event_id")
+	}
+
+	replay, sub := service.SubscribeRuleEvents(lastEventID)
+	defer sub.Close()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no") // nginx 프록시 버퍼링 비활성화
+
+	for _, event := range replay {
+		writeRuleEvent(c, event)
+	}
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-c.Request.Context().Done():
+			return false
+		case event, ok := <-sub.C:
+			if !ok {
+				return false
+			}
+			writeRuleEvent(c, event)
+		case <-heartbeat.C:
+			io.WriteString(w, ": keepalive\n\n")
+		}
+		return true
+	})
+}
+
+func writeRuleEvent(c *gin.Context, event models.RuleChangeEvent) {
+	c.Render(-1, sse.Event{
+		Id:    event.ResourceVersion,
+		Event: "rule_change",
+		Data:  event,
+	})
+}
+
+// GetRuleHistory handles GET /api/v1/rules/history
+func (h *RuleHandler) GetRuleHistory(c *gin.Context) {
+	service, ok := h.ruleset(c)
+	if !ok {
+		return
+	}
+	response, err := service.GetRuleHistory()
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -52,3 +634,142 @@ func (h *RuleHandler) UpdateRules(c *gin.Context) {
 	c.JSON(http.StatusOK, response)
 }
 
+// GetRuleRevision handles GET /api/v1/rules/history/:rev
+func (h *RuleHandler) GetRuleRevision(c *gin.Context) {
+	service, ok := h.ruleset(c)
+	if !ok {
+		return
+	}
+	rev, ok := parseRevision(c)
+	if !ok {
+		return
+	}
+
+	revision, err := service.GetRuleRevision(rev)
+	if err != nil {
+		respondRuleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, revision)
+}
+
+// DiffRuleRevision handles GET /api/v1/rules/history/:rev/diff (live ruleset -> revision)
+func (h *RuleHandler) DiffRuleRevision(c *gin.Context) {
+	service, ok := h.ruleset(c)
+	if !ok {
+		return
+	}
+	rev, ok := parseRevision(c)
+	if !ok {
+		return
+	}
+
+	diff, err := service.DiffRuleRevision(rev)
+	if err != nil {
+		respondRuleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, diff)
+}
+
+// RollbackRules handles POST /api/v1/rules/rollback/:rev. If-Match is optional here;
+// without it the rollback applies to whatever is live.
+func (h *RuleHandler) RollbackRules(c *gin.Context) {
+	service, ok := h.ruleset(c)
+	if !ok {
+		return
+	}
+	rev, ok := parseRevision(c)
+	if !ok {
+		return
+	}
+
+	expectedVersion := parseIfMatch(c.GetHeader("If-Match"))
+	if expectedVersion == "" {
+		expectedVersion = services.AnyResourceVersion
+	}
+
+	response, err := service.RollbackRules(rev, expectedVersion, requestAuthor(c))
+	if err != nil {
+		respondRuleError(c, err)
+		return
+	}
+
+	c.Header("ETag", formatETag(response.ResourceVersion))
+	c.JSON(http.StatusOK, response)
+}
+
+func parseRevision(c *gin.Context) (int64, bool) {
+	rev, err := strconv.ParseInt(c.Param("rev"), 10, 64)
+	if err != nil || rev <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "rev must be a positive integer"})
+		return 0, false
+	}
+	return rev, true
+}
+
+// requestAuthor names the caller for audit records
+func requestAuthor(c *gin.Context) string {
+	if identity := auth.IdentityFrom(c); identity != nil {
+		return identity.Subject
+	}
+	return "anonymous"
+}
+
+// respondRuleError maps RuleService errors to HTTP status codes
+func respondRuleError(c *gin.Context, err error) {
+	var conflict *services.RuleConflictError
+	var invalid *services.RuleValidationError
+	var failed *services.RuleTestError
+	switch {
+	case errors.As(err, &failed):
+		c.JSON(http.StatusBadRequest, models.RuleTestFailureResponse{
+			Error: err.Error(),
+			Tests: failed.Report,
+		})
+	case errors.As(err, &invalid):
+		c.JSON(http.StatusBadRequest, models.RuleValidationResponse{
+			Error:    err.Error(),
+			Problems: invalid.Problems,
+			Warnings: invalid.Warnings,
+		})
+	case errors.As(err, &conflict):
+		c.JSON(http.StatusConflict, models.RuleConflictResponse{
+			Error:                  conflict.Error(),
+			CurrentResourceVersion: conflict.ResourceVersion,
+			Current:                conflict.Current,
+			Diff:                   conflict.Diff,
+		})
+	case errors.Is(err, services.ErrPreconditionRequired):
+		c.JSON(http.StatusPreconditionRequired, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrRevisionNotFound), errors.Is(err, services.ErrCandidateNotFound),
+		errors.Is(err, services.ErrRuleNotFound), errors.Is(err, services.ErrRulesetNotFound),
+		errors.Is(err, services.ErrTemplateNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrRuleExists), errors.Is(err, services.ErrTemplateExists),
+		errors.Is(err, services.ErrTemplateConflict), errors.Is(err, services.ErrTemplateInUse):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrUnsupportedPatchType):
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrInvalidRuleSet), errors.Is(err, services.ErrInvalidEngineReport),
+		errors.Is(err, services.ErrInvalidPatch), errors.Is(err, services.ErrInvalidSimulation),
+		errors.Is(err, services.ErrInvalidImport), errors.Is(err, services.ErrInvalidTemplate):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+func formatETag(resourceVersion string) string {
+	return `"` + resourceVersion + `"`
+}
+
+// parseIfMatch extracts the resourceVersion from an If-Match header ("*" is passed through)
+func parseIfMatch(header string) string {
+	header = strings.TrimSpace(header)
+	header = strings.TrimPrefix(header, "W/")
+	return strings.Trim(header, `"`)
+}
+
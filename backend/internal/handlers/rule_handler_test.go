@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"admin_server/backend/internal/config"
+	"admin_server/backend/internal/models"
+	"admin_server/backend/internal/services"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+const testRuleYAML = `ruleset_version: 1.0.0
+description: test rules
+rules:
+  - rule_id: R1
+    description: exec of R1
+    severity: high
+    conditions:
+      - {field: comm, operator: equals, value: R1}
+`
+
+const updatedRulesJSON = `{"ruleset_version":"1.1.0","description":"test rules","rules":[
+  {"rule_id":"R1","description":"exec of R1","severity":"high","conditions":[{"field":"comm","operator":"equals","value":"R1"}]},
+  {"rule_id":"R2","description":"exec of R2","severity":"high","conditions":[{"field":"comm","operator":"equals","value":"R2"}]}]}`
+
+func newTestRuleRouter(t *testing.T) *gin.Engine {
+	t.Helper()
+	clientset := fake.NewClientset(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "rule-policy-config", Namespace: "default", ResourceVersion: "7"},
+		Data:       map[string]string{"rule.yaml": testRuleYAML},
+	})
+	cfg := &config.Config{
+		Namespace:        "default",
+		ConfigMapName:    "rule-policy-config",
+		ConfigMapKey:     "rule.yaml",
+		RuleHistoryStore: "memory",
+	}
+	service, err := services.NewRuleService(cfg, clientset, nil, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	handler := NewRuleHandler(service, nil)
+	router.GET("/api/v1/rules", handler.GetRules)
+	router.PUT("/api/v1/rules", handler.UpdateRules)
+	return router
+}
+
+func putRules(router *gin.Engine, ifMatch string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPut, "/api/v1/rules", strings.NewReader(updatedRulesJSON))
+	req.Header.Set("Content-Type", "application/json")
+	if ifMatch != "" {
+		req.Header.Set("If-Match", ifMatch)
+	}
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestUpdateRulesWithoutIfMatchIs428(t *testing.T) {
+	rec := putRules(newTestRuleRouter(t), "")
+	if rec.Code != http.StatusPreconditionRequired {
+		t.Fatalf("status = %d, want 428: %s", rec.Code, rec.Body)
+	}
+}
+
+func TestUpdateRulesWithStaleETagIs409WithDiff(t *testing.T) {
+	rec := putRules(newTestRuleRouter(t), `"6"`)
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("status = %d, want 409: %s", rec.Code, rec.Body)
+	}
+	var response models.RuleConflictResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+		t.Fatal(err)
+	}
+	if response.CurrentResourceVersion != "7" || response.Current == nil || response.Current.RulesetVersion != "1.0.0" {
+		t.Fatalf("conflict response = %+v, want the current ruleset at resourceVersion 7", response)
+	}
+	if len(response.Diff.Added) != 1 || response.Diff.Added[0].RuleID != "R2" {
+		t.Fatalf("diff = %+v, want R2 added", response.Diff)
+	}
+}
+
+func TestUpdateRulesWithETagFromGet(t *testing.T) {
+	router := newTestRuleRouter(t)
+	get := httptest.NewRecorder()
+	router.ServeHTTP(get, httptest.NewRequest(http.MethodGet, "/api/v1/rules", nil))
+	etag := get.Header().Get("ETag")
+	if etag == "" {
+		t.Fatalf("GET /api/v1/rules returned no ETag")
+	}
+
+	rec := putRules(router, etag)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body)
+	}
+	if rec.Header().Get("ETag") == "" {
+		t.Fatal("PUT /api/v1/rules returned no ETag")
+	}
+}
@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"admin_server/backend/internal/models"
+	"admin_server/backend/internal/services"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RuleTemplateHandler struct {
+	service *services.RuleTemplateService
+	rules   *services.RuleService
+}
+
+func NewRuleTemplateHandler(service *services.RuleTemplateService, rules *services.RuleService) *RuleTemplateHandler {
+	return &RuleTemplateHandler{
+		service: service,
+		rules:   rules,
+	}
+}
+
+// GetRuleTemplates handles GET /api/v1/rule-templates
+func (h *RuleTemplateHandler) GetRuleTemplates(c *gin.Context) {
+	templates, resourceVersion, err := h.service.ListTemplates()
+	if err != nil {
+		respondRuleError(c, err)
+		return
+	}
+
+	if resourceVersion != "" {
+		c.Header("ETag", formatETag(resourceVersion))
+	}
+	c.JSON(http.StatusOK, templates)
+}
+
+// GetRuleTemplate handles GET /api/v1/rule-templates/:template_id, listing the rules
+// built from the template in every ruleset
+func (h *RuleTemplateHandler) GetRuleTemplate(c *gin.Context) {
+	templateID := c.Param("template_id")
+	template, resourceVersion, err := h.service.GetTemplate(templateID)
+	if err != nil {
+		respondRuleError(c, err)
+		return
+	}
+
+	c.Header("ETag", formatETag(resourceVersion))
+	c.JSON(http.StatusOK, models.RuleTemplateResponse{
+		RuleTemplate: *template,
+		UsedBy:       h.rules.TemplateUsage(templateID),
+	})
+}
+
+// CreateRuleTemplate handles POST /api/v1/rule-templates/:template_id. If-Match
+// (the template ConfigMap's ETag) is optional.
+func (h *RuleTemplateHandler) CreateRuleTemplate(c *gin.Context) {
+	var template models.RuleTemplate
+	if err := c.ShouldBindJSON(&template); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	templateID := c.Param("template_id")
+	response, err := h.service.CreateTemplate(templateID, &template, parseIfMatch(c.GetHeader("If-Match")), requestAuthor(c))
+	if err != nil {
+		respondRuleError(c, err)
+		return
+	}
+	response.UsedBy = h.rules.TemplateUsage(templateID)
+
+	c.Header("ETag", formatETag(response.ResourceVersion))
+	c.JSON(http.StatusCreated, response)
+}
+
+// ReplaceRuleTemplate handles PUT /api/v1/rule-templates/:template_id. Rules built from
+// the template keep their expanded conditions until their ruleset is next written;
+// used_by lists them.
+func (h *RuleTemplateHandler) ReplaceRuleTemplate(c *gin.Context) {
+	var template models.RuleTemplate
+	if err := c.ShouldBindJSON(&template); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	templateID := c.Param("template_id")
+	response, err := h.service.ReplaceTemplate(templateID, &template, parseIfMatch(c.GetHeader("If-Match")), requestAuthor(c))
+	if err != nil {
+		respondRuleError(c, err)
+		return
+	}
+	response.UsedBy = h.rules.TemplateUsage(templateID)
+
+	c.Header("ETag", formatETag(response.ResourceVersion))
+	c.JSON(http.StatusOK, response)
+}
+
+// DeleteRuleTemplate handles DELETE /api/v1/rule-templates/:template_id. A template
+// that rules are still built from is refused with 409 and the rules in used_by.
+func (h *RuleTemplateHandler) DeleteRuleTemplate(c *gin.Context) {
+	templateID := c.Param("template_id")
+	if usedBy := h.rules.TemplateUsage(templateID); len(usedBy) > 0 {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":   fmt.Sprintf("%s: %s is used by %d rules", services.ErrTemplateInUse, templateID, len(usedBy)),
+			"used_by": usedBy,
+		})
+		return
+	}
+
+	response, err := h.service.DeleteTemplate(templateID, parseIfMatch(c.GetHeader("If-Match")), requestAuthor(c))
+	if err != nil {
+		respondRuleError(c, err)
+		return
+	}
+	response.UsedBy = []string{}
+
+	c.Header("ETag", formatETag(response.ResourceVersion))
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"admin_server/backend/internal/auth"
+	"admin_server/backend/internal/models"
+	"admin_server/backend/internal/services"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SilenceHandler struct {
+	service *services.SilenceService
+}
+
+func NewSilenceHandler(service *services.SilenceService) *SilenceHandler {
+	return &SilenceHandler{
+		service: service,
+	}
+}
+
+// GetSilences handles GET /api/v1/silences (?expired=true includes expired silences)
+func (h *SilenceHandler) GetSilences(c *gin.Context) {
+	response, err := h.service.GetSilences(c.Query("expired") == "true")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetSilence handles GET /api/v1/silences/:id
+func (h *SilenceHandler) GetSilence(c *gin.Context) {
+	silence, err := h.service.GetSilence(c.Param("id"))
+	if err != nil {
+		respondSilenceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, silence)
+}
+
+// CreateSilence handles POST /api/v1/silences
+func (h *SilenceHandler) CreateSilence(c *gin.Context) {
+	var silence models.Silence
+	if err := c.ShouldBindJSON(&silence); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if identity := auth.IdentityFrom(c); identity != nil {
+		silence.CreatedBy = identity.Subject
+	}
+
+	created, err := h.service.CreateSilence(&silence)
+	if err != nil {
+		respondSilenceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, created)
+}
+
+// UpdateSilence handles PUT /api/v1/silences/:id
+func (h *SilenceHandler) UpdateSilence(c *gin.Context) {
+	var silence models.Silence
+	if err := c.ShouldBindJSON(&silence); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// created_by는 서비스에서 기존 값을 유지하고, 수정한 사용자는 updated_by로 기록
+	if identity := auth.IdentityFrom(c); identity != nil {
+		silence.UpdatedBy = identity.Subject
+	}
+
+	updated, err := h.service.UpdateSilence(c.Param("id"), &silence)
+	if err != nil {
+		respondSilenceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, updated)
+}
+
+// DeleteSilence handles DELETE /api/v1/silences/:id
+func (h *SilenceHandler) DeleteSilence(c *gin.Context) {
+	if err := h.service.DeleteSilence(c.Param("id")); err != nil {
+		respondSilenceError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func respondSilenceError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrSilenceNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrInvalidSilence):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
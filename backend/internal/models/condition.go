@@ -0,0 +1,96 @@
+package models
+
+import "encoding/json"
+
+// Condition group kinds
+const (
+	ConditionAll = "all"
+	ConditionAny = "any"
+	ConditionNot = "not"
+)
+
+// Condition represents a condition in a rule: either a leaf test of one field
+// (Field, Operator, Value) or a group combining nested conditions, where All
+// matches when every child matches, Any when at least one does and Not when its
+// single child does not. A node is one or the other, never both.
+//
+//	conditions:
+//	  - field: syscall_name
+//	    operator: equals
+//	    value: execve
+//	  - any:
+//	      - {field: path, operator: prefix, value: /etc}
+//	      - {field: path, operator: prefix, value: /root}
+//	  - not: {field: comm, operator: in, value: [sshd]}
+type Condition struct {
+	Field    string      `json:"field" yaml:"field"`
+	Operator string      `json:"operator" yaml:"operator"`
+	Value    interface{} `json:"value" yaml:"value"`
+
+	All []Condition `json:"all,omitempty" yaml:"all,omitempty"`
+	Any []Condition `json:"any,omitempty" yaml:"any,omitempty"`
+	Not *Condition  `json:"not,omitempty" yaml:"not,omitempty"`
+}
+
+// conditionLeaf is the encoded shape of a leaf, so it keeps writing "value: 0"
+type conditionLeaf struct {
+	Field    string      `json:"field" yaml:"field"`
+	Operator string      `json:"operator" yaml:"operator"`
+	Value    interface{} `json:"value" yaml:"value"`
+}
+
+// IsGroup reports whether the condition combines nested conditions
+func (c Condition) IsGroup() bool {
+	return c.All != nil || c.Any != nil || c.Not != nil
+}
+
+// IsLeaf reports whether the condition tests a field
+func (c Condition) IsLeaf() bool {
+	return c.Field != "" || c.Operator != "" || c.Value != nil
+}
+
+// GroupKind returns ConditionAll, ConditionAny or ConditionNot for a group, or ""
+// for a leaf. A node mixing several kinds reports the first of not, all, any.
+func (c Condition) GroupKind() string {
+	switch {
+	case c.Not != nil:
+		return ConditionNot
+	case c.All != nil:
+		return ConditionAll
+	case c.Any != nil:
+		return ConditionAny
+	}
+	return ""
+}
+
+// encoded returns the shape a Condition is written in: a leaf as conditionLeaf, and a
+// group as only the group keys that are set. An empty group is written as "all: []"
+// rather than dropped, so it does not read back as an empty leaf; validation rejects it.
+func (c Condition) encoded() interface{} {
+	if !c.IsGroup() {
+		return conditionLeaf{Field: c.Field, Operator: c.Operator, Value: c.Value}
+	}
+	node := map[string]interface{}{}
+	if c.IsLeaf() {
+		// keep a node mixing both shapes intact so validation can report it
+		node["field"], node["operator"], node["value"] = c.Field, c.Operator, c.Value
+	}
+	if c.All != nil {
+		node[ConditionAll] = c.All
+	}
+	if c.Any != nil {
+		node[ConditionAny] = c.Any
+	}
+	if c.Not != nil {
+		node[ConditionNot] = c.Not
+	}
+	return node
+}
+
+func (c Condition) MarshalJSON() ([]byte, error) {
+	return json.Marshal(c.encoded())
+}
+
+func (c Condition) MarshalYAML() (interface{}, error) {
+	return c.encoded(), nil
+}
@@ -0,0 +1,50 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestConditionEmptyGroupsRoundTrip(t *testing.T) {
+	for _, cond := range []Condition{
+		{All: []Condition{}},
+		{Any: []Condition{}},
+		{Field: "comm", Operator: "equals", Value: "sh", All: []Condition{}},
+	} {
+		raw, err := json.Marshal(cond)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var fromJSON Condition
+		if err := json.Unmarshal(raw, &fromJSON); err != nil {
+			t.Fatal(err)
+		}
+		data, err := yaml.Marshal(cond)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var fromYAML Condition
+		if err := yaml.Unmarshal(data, &fromYAML); err != nil {
+			t.Fatal(err)
+		}
+
+		for encoding, decoded := range map[string]Condition{string(raw): fromJSON, string(data): fromYAML} {
+			if decoded.GroupKind() != cond.GroupKind() || decoded.IsLeaf() != cond.IsLeaf() {
+				t.Errorf("%s read back as group %q (leaf %v), want group %q (leaf %v)",
+					encoding, decoded.GroupKind(), decoded.IsLeaf(), cond.GroupKind(), cond.IsLeaf())
+			}
+		}
+	}
+}
+
+func TestConditionLeafKeepsZeroValue(t *testing.T) {
+	data, err := yaml.Marshal(Condition{Field: "uid", Operator: "equals", Value: 0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "field: uid\noperator: equals\nvalue: 0\n"; string(data) != want {
+		t.Fatalf("leaf encoded as %q, want %q", data, want)
+	}
+}
@@ -7,26 +7,272 @@ type RuleSet struct {
 	Rules          []Rule `json:"rules" yaml:"rules"`
 }
 
-// Rule represents a single security rule
+// Rule represents a single security rule. The optional fields are omitted from
+// rule.yaml when unset, so older files round-trip unchanged.
 type Rule struct {
 	RuleID      string      `json:"rule_id" yaml:"rule_id"`
 	Description string      `json:"description" yaml:"description"`
-	Conditions  []Condition `json:"conditions" yaml:"conditions"`
+	Enabled     *bool       `json:"enabled,omitempty" yaml:"enabled,omitempty"`   // unset means enabled
+	Severity    string      `json:"severity,omitempty" yaml:"severity,omitempty"` // one of SeverityLevels
+	Tags        []string    `json:"tags,omitempty" yaml:"tags,omitempty"`
+	Action      string      `json:"action,omitempty" yaml:"action,omitempty"` // unset means RuleActionAlert
+	Owner       string      `json:"owner,omitempty" yaml:"owner,omitempty"`
+	Template    string      `json:"template,omitempty" yaml:"template,omitempty"` // RuleTemplate the conditions are expanded from
+	Params      RuleParams  `json:"params,omitempty" yaml:"params,omitempty"`     // values for the template's parameters
+	Conditions  []Condition `json:"conditions" yaml:"conditions"`                 // implicitly an all-group
+	Tests       []RuleTest  `json:"tests,omitempty" yaml:"tests,omitempty"`
 }
 
-// Condition represents a condition in a rule
-type Condition struct {
-	Field    string      `json:"field" yaml:"field"`
-	Operator string      `json:"operator" yaml:"operator"`
-	Value    interface{} `json:"value" yaml:"value"`
+// RuleParams maps template parameter names to values (strings, numbers or lists)
+type RuleParams map[string]interface{}
+
+// RuleTest is a sample event with the outcome expected from its rule
+type RuleTest struct {
+	Name   string                 `json:"name,omitempty" yaml:"name,omitempty"`
+	Event  map[string]interface{} `json:"event" yaml:"event"`
+	Expect string                 `json:"expect" yaml:"expect"` // RuleTestMatch or RuleTestNoMatch
+}
+
+// RuleTest expectations
+const (
+	RuleTestMatch   = "match"
+	RuleTestNoMatch = "no_match"
+)
+
+// Rule actions the engine takes on a match
+const (
+	RuleActionAlert = "alert"
+	RuleActionBlock = "block"
+	RuleActionKill  = "kill"
+)
+
+// RuleActions lists the valid Rule.Action values
+var RuleActions = []string{RuleActionAlert, RuleActionBlock, RuleActionKill}
+
+// IsEnabled reports whether the rule engine should evaluate the rule
+func (r Rule) IsEnabled() bool {
+	return r.Enabled == nil || *r.Enabled
+}
+
+// EffectiveAction returns the action, defaulting to RuleActionAlert
+func (r Rule) EffectiveAction() string {
+	if r.Action == "" {
+		return RuleActionAlert
+	}
+	return r.Action
 }
 
 // UpdateRulesResponse represents the response for updating rules
 type UpdateRulesResponse struct {
-	Status     string `json:"status"`
-	Message    string `json:"message"`
-	NewVersion string `json:"new_version,omitempty"`
+	Status          string `json:"status"`
+	Message         string `json:"message"`
+	NewVersion      string `json:"new_version,omitempty"`
+	ResourceVersion string `json:"resource_version,omitempty"` // new ConfigMap resourceVersion (ETag)
+
+	Warnings []ValidationProblem `json:"warnings,omitempty"` // e.g. syscalls no workload can call
+	Reload   []ReloadResult      `json:"reload,omitempty"`   // rule engine reload notifications
+}
+
+// RuleReloadEvent tells rule engines that a rule ConfigMap changed
+type RuleReloadEvent struct {
+	Ruleset         string `json:"ruleset"`
+	Namespace       string `json:"namespace"`
+	ConfigMap       string `json:"configmap"`
+	RulesetVersion  string `json:"ruleset_version"`
+	ResourceVersion string `json:"resource_version"`
+	Timestamp       string `json:"timestamp"`
+}
+
+// ReloadResult is the outcome of one reload notification
+type ReloadResult struct {
+	Notifier string `json:"notifier"` // http, rollout or redis
+	Target   string `json:"target"`
+	Status   string `json:"status"` // ok, no_subscribers or failed
+	Error    string `json:"error,omitempty"`
+}
+
+// EngineReport is sent by a rule engine instance after it loads a ruleset
+type EngineReport struct {
+	Instance        string `json:"instance"`
+	RulesetVersion  string `json:"ruleset_version"`
+	ResourceVersion string `json:"resource_version"`
+	LoadedAt        string `json:"loaded_at"`
+}
+
+// EngineStatus is the last report of a rule engine instance
+type EngineStatus struct {
+	EngineReport
+	LastSeen string `json:"last_seen"`
+	InSync   bool   `json:"in_sync"` // loaded ruleset_version matches the live ConfigMap
+	Stale    bool   `json:"stale"`   // no report within RULE_ENGINE_STALE_AFTER
+}
+
+// EnginesResponse represents the response for rule engine instances
+type EnginesResponse struct {
+	LiveVersion string         `json:"live_version"`
+	Engines     []EngineStatus `json:"engines"`
+}
+
+// RuleChange pairs the live and proposed versions of a rule
+type RuleChange struct {
+	RuleID   string `json:"rule_id"`
+	Current  Rule   `json:"current"`
+	Proposed Rule   `json:"proposed"`
+}
+
+// RuleSetDiff describes how a proposed RuleSet differs from another RuleSet
+type RuleSetDiff struct {
+	RulesetVersionFrom string       `json:"ruleset_version_from"`
+	RulesetVersionTo   string       `json:"ruleset_version_to"`
+	DescriptionChanged bool         `json:"description_changed"`
+	Added              []Rule       `json:"added"`
+	Removed            []Rule       `json:"removed"`
+	Modified           []RuleChange `json:"modified"`
+}
+
+// RuleConflictResponse is returned with 409 when the rule ConfigMap changed
+// after the caller read it
+type RuleConflictResponse struct {
+	Error                  string      `json:"error"`
+	CurrentResourceVersion string      `json:"current_resource_version"`
+	Current                *RuleSet    `json:"current"`
+	Diff                   RuleSetDiff `json:"diff"` // from the live RuleSet to the caller's
 }
+
+// Rule ConfigMap change event types
+const (
+	RuleEventAdded   = "added"
+	RuleEventUpdated = "updated"
+	RuleEventDeleted = "deleted"
+)
+
+// RuleChangeEvent is broadcast on GET /api/v1/rules/events whenever the watched
+// rule ConfigMap changes, whoever changed it
+type RuleChangeEvent struct {
+	Type            string       `json:"type"` // added, updated or deleted
+	ConfigMap       string       `json:"configmap"`
+	ResourceVersion string       `json:"resource_version"`
+	RulesetVersion  string       `json:"ruleset_version,omitempty"`
+	FieldManager    string       `json:"field_manager,omitempty"` // latest managedFields manager, e.g. kubectl-edit
+	Author          string       `json:"author,omitempty"`        // admin user for changes made through this server, else FieldManager
+	Timestamp       string       `json:"timestamp"`
+	Diff            *RuleSetDiff `json:"diff,omitempty"`  // from the previously cached RuleSet
+	Error           string       `json:"error,omitempty"` // rule.yaml could not be parsed
+}
+
+// RuleRevision is a snapshot of a RuleSet taken when it was replaced
+type RuleRevision struct {
+	Revision        int64    `json:"revision"`
+	RulesetVersion  string   `json:"ruleset_version"`
+	ResourceVersion string   `json:"resource_version"` // ConfigMap resourceVersion of the snapshot
+	Author          string   `json:"author"`           // who replaced this ruleset
+	Timestamp       string   `json:"timestamp"`        // when it was replaced
+	RuleCount       int      `json:"rule_count"`
+	RuleSet         *RuleSet `json:"ruleset,omitempty"`
+}
+
+// RuleHistoryResponse represents the response for rule history
+type RuleHistoryResponse struct {
+	Revisions []RuleRevision `json:"revisions"`
+}
+
+// RuleDeployment describes the RuleSet held by one rule ConfigMap
+type RuleDeployment struct {
+	ConfigMap       string `json:"configmap"`
+	RulesetVersion  string `json:"ruleset_version"`
+	ResourceVersion string `json:"resource_version"`
+	RuleCount       int    `json:"rule_count"`
+	StagedBy        string `json:"staged_by,omitempty"` // candidate only
+	StagedAt        string `json:"staged_at,omitempty"` // candidate only
+}
+
+// RuleTemplate is a reusable condition list with ${name} placeholders. A rule naming
+// it in Template gets the conditions with its Params substituted.
+type RuleTemplate struct {
+	TemplateID  string              `json:"template_id" yaml:"template_id"`
+	Description string              `json:"description" yaml:"description"`
+	Parameters  []TemplateParameter `json:"parameters,omitempty" yaml:"parameters,omitempty"`
+	Conditions  []Condition         `json:"conditions" yaml:"conditions"`
+}
+
+// TemplateParameter declares a placeholder of a RuleTemplate
+type TemplateParameter struct {
+	Name        string      `json:"name" yaml:"name"`
+	Description string      `json:"description,omitempty" yaml:"description,omitempty"`
+	Default     interface{} `json:"default,omitempty" yaml:"default,omitempty"` // parameters without a default are required
+}
+
+// RuleTemplateSet is the content of the template ConfigMap
+type RuleTemplateSet struct {
+	Templates []RuleTemplate `json:"templates" yaml:"templates"`
+}
+
+// RuleTemplateResponse is a template with the rules built from it
+type RuleTemplateResponse struct {
+	RuleTemplate
+	UsedBy []string `json:"used_by"` // "ruleset/rule_id"
+}
+
+// RuleTemplateUpdateResponse represents the response for template changes
+type RuleTemplateUpdateResponse struct {
+	Status          string   `json:"status"`
+	Message         string   `json:"message"`
+	ResourceVersion string   `json:"resource_version,omitempty"` // new template ConfigMap resourceVersion (ETag)
+	UsedBy          []string `json:"used_by"`                    // "ruleset/rule_id" of the rules built from the template
+}
+
+// Ruleset identifies one rule ConfigMap managed by this server
+type Ruleset struct {
+	Name               string `json:"name"`
+	Namespace          string `json:"namespace"`
+	ConfigMap          string `json:"configmap"`
+	Key                string `json:"key"` // ConfigMap data key holding the RuleSet YAML
+	CandidateConfigMap string `json:"candidate_configmap"`
+	Default            bool   `json:"default"` // served by the unscoped /api/v1/rules endpoints
+}
+
+// RulesetStatus is a registered ruleset and the RuleSet it currently holds
+type RulesetStatus struct {
+	Ruleset
+	RulesetVersion  string `json:"ruleset_version,omitempty"`
+	ResourceVersion string `json:"resource_version,omitempty"`
+	RuleCount       int    `json:"rule_count"`
+	Error           string `json:"error,omitempty"` // the ConfigMap could not be read
+}
+
+// RulesetsResponse represents the response for listing rulesets
+type RulesetsResponse struct {
+	Rulesets []RulesetStatus `json:"rulesets"`
+}
+
+// RuleStatusResponse reports the live and candidate (shadow) rulesets
+type RuleStatusResponse struct {
+	Live      *RuleDeployment `json:"live"`
+	Candidate *RuleDeployment `json:"candidate"`      // nil when nothing is staged
+	Diff      *RuleSetDiff    `json:"diff,omitempty"` // from live to candidate
+}
+
+// ValidationProblem is a single rule validation failure located by a JSON pointer
+// into the submitted RuleSet, e.g. "/rules/2/conditions/0/value"
+type ValidationProblem struct {
+	Pointer string `json:"pointer"`
+	Message string `json:"message"`
+}
+
+// RuleValidationResponse is returned with 400 when a RuleSet fails validation
+type RuleValidationResponse struct {
+	Error    string              `json:"error"`
+	Problems []ValidationProblem `json:"problems"`
+	Warnings []ValidationProblem `json:"warnings,omitempty"`
+}
+
+// ValidateRulesResponse represents the response for a dry-run validation
+type ValidateRulesResponse struct {
+	Valid    bool                `json:"valid"`
+	Problems []ValidationProblem `json:"problems"`
+	Warnings []ValidationProblem `json:"warnings"`
+}
+
 type SyscallArg struct {
 	Type string `json:"type"`
 	Name string `json:"name"`
@@ -55,11 +301,104 @@ type Alert struct {
 	PodName         string                 `json:"pod_name"`
 	Namespace       string                 `json:"namespace"`
 	SyscallLog      map[string]interface{} `json:"syscall_log"`
+
+	// IncidentID links the alert to the incident it was grouped into
+	IncidentID string `json:"incident_id,omitempty"`
+
+	// Silenced alerts are stored but not notified or streamed
+	Silenced  bool   `json:"silenced"`
+	SilenceID string `json:"silence_id,omitempty"`
+
+	// Lifecycle
+	State    string            `json:"state"`
+	Assignee string            `json:"assignee,omitempty"`
+	Comments []AlertComment    `json:"comments,omitempty"`
+	History  []AlertTransition `json:"history,omitempty"`
+}
+
+// Alert lifecycle states
+const (
+	AlertStateOpen          = "open"
+	AlertStateAcknowledged  = "acknowledged"
+	AlertStateResolved      = "resolved"
+	AlertStateFalsePositive = "false_positive"
+)
+
+// AlertComment is a single entry in an alert's comment thread
+type AlertComment struct {
+	Author    string `json:"author"`
+	Text      string `json:"text"`
+	Timestamp string `json:"timestamp"`
+}
+
+// AlertTransition records a state change of an alert
+type AlertTransition struct {
+	From      string `json:"from"`
+	To        string `json:"to"`
+	Actor     string `json:"actor"`
+	Timestamp string `json:"timestamp"`
+}
+
+// UpdateAlertRequest represents the body of PATCH /api/v1/alerts/:id.
+// Omitted fields are left unchanged.
+type UpdateAlertRequest struct {
+	State    *string `json:"state"`
+	Assignee *string `json:"assignee"`
+	Actor    string  `json:"actor"`
+}
+
+// AddAlertCommentRequest represents the body of POST /api/v1/alerts/:id/comments
+type AddAlertCommentRequest struct {
+	Author string `json:"author"`
+	Text   string `json:"text"`
+}
+
+// Incident groups repeated alerts with the same rule_id, namespace and pod_name
+type Incident struct {
+	IncidentID      string `json:"incident_id"`
+	RuleID          string `json:"rule_id"`
+	RuleDescription string `json:"rule_description"`
+	Namespace       string `json:"namespace"`
+	PodName         string `json:"pod_name"`
+	Severity        string `json:"severity"` // highest severity seen
+	FirstSeen       string `json:"first_seen"`
+	LastSeen        string `json:"last_seen"`
+	Count           int    `json:"count"`
+}
+
+// IncidentsResponse represents the response for incidents
+type IncidentsResponse struct {
+	Incidents []Incident `json:"incidents"`
+	Total     int        `json:"total"`
+}
+
+// Silence mutes alerts matching all of its non-empty matchers between StartsAt and EndsAt.
+// Matchers use path.Match glob syntax (e.g. "RULE_A*").
+type Silence struct {
+	SilenceID string `json:"silence_id"`
+	RuleID    string `json:"rule_id,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+	PodName   string `json:"pod_name,omitempty"`
+	Severity  string `json:"severity,omitempty"`
+	StartsAt  string `json:"starts_at"`
+	EndsAt    string `json:"ends_at"`
+	CreatedBy string `json:"created_by"`
+	Reason    string `json:"reason"`
+	CreatedAt string `json:"created_at"`
+	UpdatedBy string `json:"updated_by,omitempty"`
+	UpdatedAt string `json:"updated_at,omitempty"`
+}
+
+// SilencesResponse represents the response for silences
+type SilencesResponse struct {
+	Silences []Silence `json:"silences"`
 }
 
 // AlertsResponse represents the response for alerts
 type AlertsResponse struct {
-	Alerts []Alert `json:"alerts"`
+	Alerts     []Alert `json:"alerts"`
+	Total      int     `json:"total"`                 // number of alerts matching the filters across all pages
+	NextCursor string  `json:"next_cursor,omitempty"` // pass as ?cursor= to fetch the next page
 }
 
 // TriggerTestRequest represents the request for triggering a test
@@ -84,3 +423,81 @@ type WebhookAlert struct {
 	Namespace       string                 `json:"namespace"`
 	SyscallLog      map[string]interface{} `json:"syscall_log"`
 }
+
+// SimulateRulesRequest is the JSON form of POST /api/v1/rules/simulate. Without
+// RuleSet the live rules are simulated.
+type SimulateRulesRequest struct {
+	RuleSet *RuleSet                 `json:"ruleset"`
+	Events  []map[string]interface{} `json:"events"`
+}
+
+// SimulateRulesResponse reports what each rule would have matched
+type SimulateRulesResponse struct {
+	RulesetVersion string              `json:"ruleset_version"`
+	Source         string              `json:"source"`  // "upload" or "alerts"
+	Events         int                 `json:"events"`  // events evaluated
+	Matched        int                 `json:"matched"` // events matching at least one enabled rule
+	Rules          []RuleSimulation    `json:"rules"`
+	Warnings       []ValidationProblem `json:"warnings"`
+}
+
+// RuleSimulation is the outcome of one rule in a simulation
+type RuleSimulation struct {
+	RuleID  string            `json:"rule_id"`
+	Enabled bool              `json:"enabled"`
+	Matches int               `json:"matches"`
+	Samples []SimulationMatch `json:"samples"`
+	Error   string            `json:"error,omitempty"` // evaluation stopped for this rule
+}
+
+// SimulationMatch is a sample event a rule matched
+type SimulationMatch struct {
+	Index   int                    `json:"index"`              // position of the event in the batch
+	AlertID string                 `json:"alert_id,omitempty"` // when the event came from a stored alert
+	Event   map[string]interface{} `json:"event"`
+}
+
+// RuleTestReport is the outcome of every test embedded in a RuleSet
+type RuleTestReport struct {
+	RulesetVersion string           `json:"ruleset_version"`
+	Total          int              `json:"total"`
+	Passed         int              `json:"passed"`
+	Failed         int              `json:"failed"`
+	Results        []RuleTestResult `json:"results"`
+}
+
+// RuleTestResult is the outcome of one embedded test
+type RuleTestResult struct {
+	RuleID  string `json:"rule_id"`
+	Index   int    `json:"index"` // position in the rule's tests
+	Name    string `json:"name,omitempty"`
+	Expect  string `json:"expect"`
+	Matched bool   `json:"matched"`
+	Passed  bool   `json:"passed"`
+	Error   string `json:"error,omitempty"`
+}
+
+// RuleTestFailureResponse is returned when a write is refused because embedded tests fail
+type RuleTestFailureResponse struct {
+	Error string          `json:"error"`
+	Tests *RuleTestReport `json:"tests"`
+}
+
+// UntranslatedConstruct is part of an imported Falco or Tetragon file that has no
+// equivalent in a RuleSet and was dropped
+type UntranslatedConstruct struct {
+	Source    string `json:"source"`    // where it appeared, e.g. rule "Terminal shell in container"
+	Construct string `json:"construct"` // the dropped expression, field or selector
+	Reason    string `json:"reason"`
+}
+
+// ImportRulesResponse is returned by POST /api/v1/rules/import
+type ImportRulesResponse struct {
+	Format       string                  `json:"format"`
+	Mode         string                  `json:"mode"`
+	DryRun       bool                    `json:"dry_run"`
+	Imported     *RuleSet                `json:"imported"` // the rules read from the file
+	Untranslated []UntranslatedConstruct `json:"untranslated"`
+	Diff         *RuleSetDiff            `json:"diff"`             // from the live RuleSet to the result
+	Result       *UpdateRulesResponse    `json:"result,omitempty"` // unset for a dry run
+}
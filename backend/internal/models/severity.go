@@ -0,0 +1,18 @@
+package models
+
+import "strings"
+
+// Severity levels from least to most severe
+var SeverityLevels = []string{"info", "low", "medium", "high", "critical"}
+
+// SeverityRank returns the position of severity in SeverityLevels (case-insensitive),
+// or -1 when it is not a known level.
+func SeverityRank(severity string) int {
+	severity = strings.ToLower(strings.TrimSpace(severity))
+	for i, level := range SeverityLevels {
+		if level == severity {
+			return i
+		}
+	}
+	return -1
+}
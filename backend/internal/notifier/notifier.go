@@ -0,0 +1,153 @@
+package notifier
+
+import (
+	"admin_server/backend/internal/config"
+	"admin_server/backend/internal/models"
+	"context"
+	"fmt"
+	"log"
+	"path"
+	"time"
+)
+
+const maxRetryBackoff = time.Minute
+
+// Sink delivers a single alert to an external system
+type Sink interface {
+	Name() string
+	Send(ctx context.Context, alert models.Alert) error
+}
+
+// Route decides which alerts a sink receives
+type Route struct {
+	MinSeverity string   // alerts below this level are skipped (empty or unknown accepts all)
+	Rules       []string // rule_id patterns in path.Match syntax; empty matches every rule
+}
+
+// Matches reports whether alert passes the severity threshold and rule routing
+func (r Route) Matches(alert models.Alert) bool {
+	if threshold := models.SeverityRank(r.MinSeverity); threshold >= 0 {
+		if models.SeverityRank(alert.Severity) < threshold {
+			return false
+		}
+	}
+	if len(r.Rules) == 0 {
+		return true
+	}
+	for _, pattern := range r.Rules {
+		if ok, _ := path.Match(pattern, alert.RuleID); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// routedSink owns a queue and a worker so one slow sink never delays another
+type routedSink struct {
+	sink  Sink
+	route Route
+	queue chan models.Alert
+}
+
+// Dispatcher fans alerts out to the configured sinks asynchronously
+type Dispatcher struct {
+	sinks      []*routedSink
+	queueSize  int
+	maxRetries int
+	backoff    time.Duration
+}
+
+// NewDispatcher creates a Dispatcher with every sink enabled in cfg
+func NewDispatcher(cfg *config.Config) *Dispatcher {
+	d := &Dispatcher{
+		queueSize:  cfg.NotifyQueueSize,
+		maxRetries: cfg.NotifyMaxRetries,
+		backoff:    cfg.NotifyRetryBackoff,
+	}
+
+	if cfg.SlackWebhookURL != "" {
+		d.AddSink(NewSlackSink(cfg.SlackWebhookURL), Route{MinSeverity: cfg.SlackMinSeverity, Rules: cfg.SlackRules})
+	}
+	if cfg.NotifyWebhookURL != "" {
+		d.AddSink(NewWebhookSink(cfg.NotifyWebhookURL, cfg.NotifyWebhookSecret), Route{MinSeverity: cfg.NotifyWebhookMinSeverity, Rules: cfg.NotifyWebhookRules})
+	}
+	if cfg.SMTPAddr != "" && len(cfg.SMTPTo) > 0 {
+		d.AddSink(NewEmailSink(cfg.SMTPAddr, cfg.SMTPUsername, cfg.SMTPPassword, cfg.SMTPFrom, cfg.SMTPTo), Route{MinSeverity: cfg.SMTPMinSeverity, Rules: cfg.SMTPRules})
+	}
+
+	return d
+}
+
+// AddSink registers a sink. It must be called before Start.
+func (d *Dispatcher) AddSink(sink Sink, route Route) {
+	queueSize := d.queueSize
+	if queueSize <= 0 {
+		queueSize = 1
+	}
+	d.sinks = append(d.sinks, &routedSink{
+		sink:  sink,
+		route: route,
+		queue: make(chan models.Alert, queueSize),
+	})
+	log.Printf("Alert notifications enabled for sink %s", sink.Name())
+}
+
+// Start launches one delivery worker per sink; workers stop when ctx is cancelled
+func (d *Dispatcher) Start(ctx context.Context) {
+	for _, rs := range d.sinks {
+		go d.worker(ctx, rs)
+	}
+}
+
+// Notify queues alert for every matching sink. It never blocks: when a sink's
+// queue is full the alert is dropped for that sink and logged.
+func (d *Dispatcher) Notify(alert models.Alert) {
+	if d == nil {
+		return
+	}
+	for _, rs := range d.sinks {
+		if !rs.route.Matches(alert) {
+			continue
+		}
+		select {
+		case rs.queue <- alert:
+		default:
+			log.Printf("WARN: Notification queue for %s is full, dropping alert %s", rs.sink.Name(), alert.AlertID)
+		}
+	}
+}
+
+func (d *Dispatcher) worker(ctx context.Context, rs *routedSink) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case alert := <-rs.queue:
+			if err := d.deliver(ctx, rs.sink, alert); err != nil {
+				log.Printf("ERROR: Failed to notify %s about alert %s: %v", rs.sink.Name(), alert.AlertID, err)
+			}
+		}
+	}
+}
+
+// deliver sends alert with exponential backoff between attempts
+func (d *Dispatcher) deliver(ctx context.Context, sink Sink, alert models.Alert) error {
+	delay := d.backoff
+	var err error
+	for attempt := 0; attempt <= d.maxRetries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(delay):
+			}
+			delay = min(delay*2, maxRetryBackoff)
+		}
+
+		if err = sink.Send(ctx, alert); err == nil {
+			return nil
+		}
+		log.Printf("WARN: Notification to %s failed (attempt %d/%d): %v", sink.Name(), attempt+1, d.maxRetries+1, err)
+	}
+	return fmt.Errorf("giving up after %d attempts: %w", d.maxRetries+1, err)
+}
@@ -0,0 +1,238 @@
+package notifier
+
+import (
+	"admin_server/backend/internal/models"
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/smtp"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// SignatureHeader carries the hex HMAC-SHA256 of the request body ("sha256=<hex>")
+const SignatureHeader = "X-Admin-Signature-256"
+
+var httpClient = &http.Client{Timeout: 10 * time.Second}
+
+// smtpTimeout bounds one email delivery, from dialing the relay to QUIT
+const smtpTimeout = 30 * time.Second
+
+// SlackSink posts alerts to a Slack incoming webhook
+type SlackSink struct {
+	url string
+}
+
+func NewSlackSink(url string) *SlackSink {
+	return &SlackSink{url: url}
+}
+
+func (s *SlackSink) Name() string { return "slack" }
+
+func (s *SlackSink) Send(ctx context.Context, alert models.Alert) error {
+	payload, err := json.Marshal(map[string]string{"text": formatAlert(alert)})
+	if err != nil {
+		return fmt.Errorf("failed to marshal Slack payload: %w", err)
+	}
+	return postJSON(ctx, s.url, payload, nil)
+}
+
+// WebhookSink posts the alert as JSON, signed with HMAC-SHA256 when a secret is set
+type WebhookSink struct {
+	url    string
+	secret string
+}
+
+func NewWebhookSink(url, secret string) *WebhookSink {
+	return &WebhookSink{url: url, secret: secret}
+}
+
+func (s *WebhookSink) Name() string { return "webhook" }
+
+func (s *WebhookSink) Send(ctx context.Context, alert models.Alert) error {
+	payload, err := json.Marshal(alert)
+	if err != nil {
+		return fmt.Errorf("failed to marshal alert: %w", err)
+	}
+
+	headers := map[string]string{}
+	if s.secret != "" {
+		headers[SignatureHeader] = "sha256=" + Sign(s.secret, payload)
+	}
+	return postJSON(ctx, s.url, payload, headers)
+}
+
+// Sign returns the hex-encoded HMAC-SHA256 of body using secret
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// EmailSink sends a plain-text email through an SMTP relay
+type EmailSink struct {
+	addr     string
+	username string
+	password string
+	from     string
+	to       []string
+}
+
+func NewEmailSink(addr, username, password, from string, to []string) *EmailSink {
+	return &EmailSink{
+		addr:     addr,
+		username: username,
+		password: password,
+		from:     from,
+		to:       to,
+	}
+}
+
+func (s *EmailSink) Name() string { return "email" }
+
+func (s *EmailSink) Send(ctx context.Context, alert models.Alert) error {
+	host, _, err := net.SplitHostPort(s.addr)
+	if err != nil {
+		return fmt.Errorf("invalid SMTP address %s: %w", s.addr, err)
+	}
+
+	// 헤더 값에 CR/LF가 들어가면 헤더를 끼워 넣을 수 있으므로 제거하고, 비ASCII는 RFC 2047로 인코딩
+	subject := fmt.Sprintf("[%s] %s", strings.ToUpper(alert.Severity), alert.RuleID)
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", headerValue(s.from))
+	fmt.Fprintf(&msg, "To: %s\r\n", headerValue(strings.Join(s.to, ", ")))
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("UTF-8", headerValue(subject)))
+	msg.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	msg.WriteString(formatAlert(alert))
+	msg.WriteString("\r\n")
+
+	// net/smtp has no context support: bound the whole exchange with a connection
+	// deadline, and pull the deadline in when ctx is cancelled, so nothing outlives ctx
+	conn, err := (&net.Dialer{Timeout: smtpTimeout}).DialContext(ctx, "tcp", s.addr)
+	if err != nil {
+		return fmt.Errorf("failed to connect to SMTP relay: %w", err)
+	}
+	defer conn.Close()
+	deadline := time.Now().Add(smtpTimeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	conn.SetDeadline(deadline)
+	stop := context.AfterFunc(ctx, func() { conn.SetDeadline(time.Now()) })
+	defer stop()
+
+	if err := s.send(conn, host, msg.Bytes()); err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	return nil
+}
+
+// send runs the SMTP exchange of smtp.SendMail over conn
+func (s *EmailSink) send(conn net.Conn, host string, msg []byte) error {
+	client, err := smtp.NewClient(conn, host)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: host}); err != nil {
+			return err
+		}
+	}
+	if s.username != "" {
+		if ok, _ := client.Extension("AUTH"); !ok {
+			return errors.New("SMTP relay does not support AUTH")
+		}
+		if err := client.Auth(smtp.PlainAuth("", s.username, s.password, host)); err != nil {
+			return err
+		}
+	}
+	if err := client.Mail(s.from); err != nil {
+		return err
+	}
+	for _, to := range s.to {
+		if err := client.Rcpt(to); err != nil {
+			return err
+		}
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// headerValue strips CR and LF so a value cannot start a new mail header
+func headerValue(value string) string {
+	return strings.NewReplacer("\r", " ", "\n", " ").Replace(value)
+}
+
+func postJSON(ctx context.Context, rawURL string, payload []byte, headers map[string]string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, rawURL, bytes.NewReader(payload))
+	if err != nil {
+		return RedactRequestError(rawURL, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return RedactRequestError(rawURL, err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("%s returned status: %d", RedactURL(rawURL), resp.StatusCode)
+	}
+	return nil
+}
+
+// RedactURL returns only the scheme and host of rawURL for logs and errors, since
+// webhook URLs such as Slack's carry their secret in the path
+func RedactURL(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || parsed.Host == "" {
+		return "<invalid url>"
+	}
+	return parsed.Scheme + "://" + parsed.Host
+}
+
+// RedactRequestError replaces the full URL that net/http puts in *url.Error (and URL
+// parse errors) with RedactURL
+func RedactRequestError(rawURL string, err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		err = urlErr.Err
+	}
+	return fmt.Errorf("request to %s failed: %w", RedactURL(rawURL), err)
+}
+
+func formatAlert(alert models.Alert) string {
+	return fmt.Sprintf("[%s] %s - %s\nPod: %s/%s\nTime: %s\nAlert ID: %s",
+		strings.ToUpper(alert.Severity), alert.RuleID, alert.RuleDescription,
+		alert.Namespace, alert.PodName, alert.Timestamp, alert.AlertID)
+}
@@ -0,0 +1,149 @@
+package notifier
+
+import (
+	"admin_server/backend/internal/models"
+	"bufio"
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeSMTPRelay accepts one connection and answers the commands EmailSink sends,
+// returning the DATA payload on the channel
+func fakeSMTPRelay(t *testing.T) (string, <-chan string) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	data := make(chan string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		text := textproto.NewConn(conn)
+		text.PrintfLine("220 fake ESMTP")
+		for {
+			line, err := text.ReadLine()
+			if err != nil {
+				return
+			}
+			switch verb := strings.ToUpper(strings.Fields(line)[0]); verb {
+			case "EHLO", "HELO":
+				text.PrintfLine("250 fake")
+			case "DATA":
+				text.PrintfLine("354 go ahead")
+				lines, err := text.ReadDotLines()
+				if err != nil {
+					return
+				}
+				data <- strings.Join(lines, "\n")
+				text.PrintfLine("250 queued")
+			case "QUIT":
+				text.PrintfLine("221 bye")
+				return
+			default:
+				text.PrintfLine("250 ok")
+			}
+		}
+	}()
+	return ln.Addr().String(), data
+}
+
+func TestEmailSinkStripsHeaderInjection(t *testing.T) {
+	addr, data := fakeSMTPRelay(t)
+	sink := NewEmailSink(addr, "", "", "alerts@example.com", []string{"oncall@example.com"})
+
+	alert := models.Alert{AlertID: "a1", Severity: "high\r\nBcc: attacker@example.com", RuleID: "R1\nX-Injected: yes"}
+	if err := sink.Send(context.Background(), alert); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	message := <-data
+	headers, _, _ := strings.Cut(message, "\n\n")
+	reader := textproto.NewReader(bufio.NewReader(strings.NewReader(headers + "\n\n")))
+	header, err := reader.ReadMIMEHeader()
+	if err != nil {
+		t.Fatalf("reading headers of %q: %v", message, err)
+	}
+	if header.Get("Bcc") != "" || header.Get("X-Injected") != "" {
+		t.Fatalf("alert fields injected headers: %v", header)
+	}
+	if subject := header.Get("Subject"); !strings.Contains(subject, "R1") {
+		t.Fatalf("Subject = %q, want the rule id", subject)
+	}
+}
+
+func TestEmailSinkEncodesNonASCIISubject(t *testing.T) {
+	addr, data := fakeSMTPRelay(t)
+	sink := NewEmailSink(addr, "", "", "alerts@example.com", []string{"oncall@example.com"})
+
+	if err := sink.Send(context.Background(), models.Alert{AlertID: "a1", Severity: "high", RuleID: "권한상승"}); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if message := <-data; !strings.Contains(message, "Subject: =?UTF-8?q?") {
+		t.Fatalf("Subject is not RFC 2047 encoded:\n%s", message)
+	}
+}
+
+func TestEmailSinkStopsWhenContextIsCancelled(t *testing.T) {
+	// 연결은 받지만 인사말을 보내지 않는 릴레이
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	go func() {
+		conn, err := ln.Accept()
+		if err == nil {
+			defer conn.Close()
+			time.Sleep(10 * time.Second)
+		}
+	}()
+
+	sink := NewEmailSink(ln.Addr().String(), "", "", "alerts@example.com", []string{"oncall@example.com"})
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(100*time.Millisecond, cancel)
+
+	start := time.Now()
+	err = sink.Send(ctx, models.Alert{AlertID: "a1", Severity: "high", RuleID: "R1"})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, want context.Canceled", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Fatalf("Send returned %v after cancel", elapsed)
+	}
+}
+
+func TestSlackSinkErrorsDoNotLeakTheWebhookURL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+	const secretPath = "/services/T000/B000/s3cretT0ken"
+
+	alert := models.Alert{AlertID: "a1", Severity: "high", RuleID: "R1"}
+	for name, url := range map[string]string{
+		"error status":       server.URL + secretPath,
+		"unreachable":        "http://127.0.0.1:1" + secretPath,
+		"unparsable address": "http://bad host" + secretPath,
+	} {
+		err := NewSlackSink(url).Send(context.Background(), alert)
+		if err == nil {
+			t.Fatalf("%s: Send succeeded", name)
+		}
+		if strings.Contains(err.Error(), "s3cretT0ken") {
+			t.Fatalf("%s: error leaks the webhook URL: %v", name, err)
+		}
+	}
+}
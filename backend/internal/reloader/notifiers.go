@@ -0,0 +1,154 @@
+package reloader
+
+import (
+	"admin_server/backend/internal/models"
+	"admin_server/backend/internal/notifier"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+)
+
+// restartedAtAnnotation is the pod template annotation `kubectl rollout restart` sets
+const restartedAtAnnotation = "kubectl.kubernetes.io/restartedAt"
+
+var httpClient = &http.Client{Timeout: 30 * time.Second}
+
+// HTTPNotifier POSTs the reload event to a rule engine reload endpoint,
+// signed like outbound alert webhooks when a secret is set
+type HTTPNotifier struct {
+	url    string
+	secret string
+}
+
+func NewHTTPNotifier(url, secret string) *HTTPNotifier {
+	return &HTTPNotifier{url: url, secret: secret}
+}
+
+func (n *HTTPNotifier) Name() string   { return "http" }
+func (n *HTTPNotifier) Target() string { return notifier.RedactURL(n.url) }
+
+func (n *HTTPNotifier) Notify(ctx context.Context, event models.RuleReloadEvent) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal reload event: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(payload))
+	if err != nil {
+		return notifier.RedactRequestError(n.url, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if n.secret != "" {
+		req.Header.Set(notifier.SignatureHeader, "sha256="+notifier.Sign(n.secret, payload))
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return notifier.RedactRequestError(n.url, err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("%s returned status: %d", n.Target(), resp.StatusCode)
+	}
+	return nil
+}
+
+// RolloutNotifier restarts a Deployment or DaemonSet the way `kubectl rollout restart`
+// does, for engines that only read rules at startup
+type RolloutNotifier struct {
+	clientset kubernetes.Interface
+	namespace string
+	kind      string // deployment or daemonset
+	name      string
+}
+
+// NewRolloutNotifier parses target as "kind/name" (in defaultNamespace) or "namespace/kind/name"
+func NewRolloutNotifier(clientset kubernetes.Interface, defaultNamespace, target string) (*RolloutNotifier, error) {
+	parts := strings.Split(target, "/")
+	n := &RolloutNotifier{clientset: clientset, namespace: defaultNamespace}
+	switch len(parts) {
+	case 2:
+		n.kind, n.name = strings.ToLower(parts[0]), parts[1]
+	case 3:
+		n.namespace, n.kind, n.name = parts[0], strings.ToLower(parts[1]), parts[2]
+	default:
+		return nil, fmt.Errorf("invalid rollout target %q (expected kind/name or namespace/kind/name)", target)
+	}
+	if n.kind != "deployment" && n.kind != "daemonset" {
+		return nil, fmt.Errorf("invalid rollout target %q: kind must be deployment or daemonset", target)
+	}
+	if n.name == "" {
+		return nil, fmt.Errorf("invalid rollout target %q: name is empty", target)
+	}
+	return n, nil
+}
+
+func (n *RolloutNotifier) Name() string   { return "rollout" }
+func (n *RolloutNotifier) Target() string { return n.namespace + "/" + n.kind + "/" + n.name }
+
+func (n *RolloutNotifier) Notify(ctx context.Context, event models.RuleReloadEvent) error {
+	patch, err := json.Marshal(map[string]interface{}{
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"annotations": map[string]string{
+						restartedAtAnnotation: time.Now().UTC().Format(time.RFC3339),
+					},
+				},
+			},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to build rollout patch: %w", err)
+	}
+
+	if n.kind == "daemonset" {
+		_, err = n.clientset.AppsV1().DaemonSets(n.namespace).Patch(ctx, n.name, types.StrategicMergePatchType, patch, metav1.PatchOptions{})
+	} else {
+		_, err = n.clientset.AppsV1().Deployments(n.namespace).Patch(ctx, n.name, types.StrategicMergePatchType, patch, metav1.PatchOptions{})
+	}
+	if err != nil {
+		return fmt.Errorf("failed to restart %s: %w", n.Target(), err)
+	}
+	return nil
+}
+
+// RedisNotifier publishes the reload event on a Redis pub/sub channel
+type RedisNotifier struct {
+	client  *redis.Client
+	channel string
+}
+
+func NewRedisNotifier(client *redis.Client, channel string) *RedisNotifier {
+	return &RedisNotifier{client: client, channel: channel}
+}
+
+func (n *RedisNotifier) Name() string   { return "redis" }
+func (n *RedisNotifier) Target() string { return n.channel }
+
+func (n *RedisNotifier) Notify(ctx context.Context, event models.RuleReloadEvent) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal reload event: %w", err)
+	}
+	receivers, err := n.client.Publish(ctx, n.channel, payload).Result()
+	if err != nil {
+		return fmt.Errorf("failed to publish reload event: %w", err)
+	}
+	if receivers == 0 {
+		return fmt.Errorf("%w on channel %s", ErrNoSubscribers, n.channel)
+	}
+	return nil
+}
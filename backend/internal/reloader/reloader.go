@@ -0,0 +1,161 @@
+package reloader
+
+import (
+	"admin_server/backend/internal/config"
+	"admin_server/backend/internal/models"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Reload result statuses
+const (
+	StatusOK     = "ok"
+	StatusFailed = "failed"
+	// StatusNoSubscribers means the event was published but no rule engine was listening
+	StatusNoSubscribers = "no_subscribers"
+)
+
+// ErrNoSubscribers is returned by a Notifier whose event reached no rule engine.
+// It is reported as StatusNoSubscribers rather than a failure.
+var ErrNoSubscribers = errors.New("no subscribers")
+
+// Notifier tells rule engines to pick up a changed rule ConfigMap
+type Notifier interface {
+	Name() string
+	Target() string
+	Notify(ctx context.Context, event models.RuleReloadEvent) error
+}
+
+// defaultRuleset is the name of the ruleset configured by CONFIG_MAP_NAME (services.DefaultRuleset)
+const defaultRuleset = "default"
+
+// Manager sends a reload event to every configured notifier of the event's ruleset and
+// reports the outcome
+type Manager struct {
+	notifiers []scopedNotifier
+	timeout   time.Duration
+}
+
+// scopedNotifier is a notifier that only serves one ruleset, or every ruleset when
+// ruleset is empty
+type scopedNotifier struct {
+	Notifier
+	ruleset string
+}
+
+// NewManager creates a Manager with the notifiers listed in RULE_RELOAD_NOTIFIERS.
+// http and redis notifiers serve every ruleset, since the event names the ruleset.
+// A rollout target restarts only for its own ruleset: "ruleset=target" binds it to a
+// RULESETS entry (kind/name resolving in that ruleset's namespace), a bare target to
+// the default ruleset. Unknown notifier names, unknown rulesets and incomplete
+// settings are startup errors.
+func NewManager(cfg *config.Config, clientset kubernetes.Interface, redisClient *redis.Client) (*Manager, error) {
+	m := &Manager{timeout: cfg.RuleReloadTimeout}
+	namespaces := rulesetNamespaces(cfg)
+
+	for _, name := range cfg.RuleReloadNotifiers {
+		switch name {
+		case "http":
+			if len(cfg.RuleReloadURLs) == 0 {
+				return nil, fmt.Errorf("reload notifier http requires RULE_RELOAD_URLS")
+			}
+			for _, url := range cfg.RuleReloadURLs {
+				m.Add(NewHTTPNotifier(url, cfg.RuleReloadSecret))
+			}
+		case "rollout":
+			if len(cfg.RuleReloadRollouts) == 0 {
+				return nil, fmt.Errorf("reload notifier rollout requires RULE_RELOAD_ROLLOUTS")
+			}
+			for _, entry := range cfg.RuleReloadRollouts {
+				ruleset, target, ok := strings.Cut(entry, "=")
+				if !ok {
+					ruleset, target = defaultRuleset, entry
+				}
+				namespace, known := namespaces[ruleset]
+				if !known {
+					return nil, fmt.Errorf("RULE_RELOAD_ROLLOUTS entry %q names unknown ruleset %q", entry, ruleset)
+				}
+				n, err := NewRolloutNotifier(clientset, namespace, target)
+				if err != nil {
+					return nil, err
+				}
+				m.AddFor(ruleset, n)
+			}
+		case "redis":
+			m.Add(NewRedisNotifier(redisClient, cfg.RuleReloadChannel))
+		default:
+			return nil, fmt.Errorf("unknown reload notifier %q (expected http, rollout or redis)", name)
+		}
+	}
+
+	return m, nil
+}
+
+// Add registers a notifier for every ruleset
+func (m *Manager) Add(n Notifier) {
+	m.notifiers = append(m.notifiers, scopedNotifier{Notifier: n})
+	log.Printf("Rule reload notifier enabled: %s %s", n.Name(), n.Target())
+}
+
+// AddFor registers a notifier that is only told about changes to the named ruleset
+func (m *Manager) AddFor(ruleset string, n Notifier) {
+	m.notifiers = append(m.notifiers, scopedNotifier{Notifier: n, ruleset: ruleset})
+	log.Printf("Rule reload notifier enabled for ruleset %s: %s %s", ruleset, n.Name(), n.Target())
+}
+
+// rulesetNamespaces maps the default ruleset and each RULESETS entry
+// ("name=namespace/configmap[/key]") to its namespace. Malformed entries are left to
+// the rule service, which rejects them at startup.
+func rulesetNamespaces(cfg *config.Config) map[string]string {
+	namespaces := map[string]string{defaultRuleset: cfg.Namespace}
+	for _, entry := range cfg.Rulesets {
+		name, location, _ := strings.Cut(entry, "=")
+		namespace, _, _ := strings.Cut(location, "/")
+		namespaces[name] = namespace
+	}
+	return namespaces
+}
+
+// Reload notifies every notifier of event.Ruleset in turn, each bounded by the reload timeout.
+// Failures are reported in the results and never returned as an error, since the
+// ConfigMap has already been written. A nil Manager notifies nothing.
+func (m *Manager) Reload(ctx context.Context, event models.RuleReloadEvent) []models.ReloadResult {
+	if m == nil || len(m.notifiers) == 0 {
+		return nil
+	}
+
+	results := make([]models.ReloadResult, 0, len(m.notifiers))
+	for _, n := range m.notifiers {
+		if n.ruleset != "" && n.ruleset != event.Ruleset {
+			continue
+		}
+		result := models.ReloadResult{Notifier: n.Name(), Target: n.Target(), Status: StatusOK}
+
+		notifyCtx := ctx
+		cancel := func() {}
+		if m.timeout > 0 {
+			notifyCtx, cancel = context.WithTimeout(ctx, m.timeout)
+		}
+		err := n.Notify(notifyCtx, event)
+		cancel()
+
+		switch {
+		case errors.Is(err, ErrNoSubscribers):
+			log.Printf("WARN: Rule reload via %s %s reached no subscribers", n.Name(), n.Target())
+			result.Status = StatusNoSubscribers
+		case err != nil:
+			log.Printf("WARN: Rule reload via %s %s failed: %v", n.Name(), n.Target(), err)
+			result.Status = StatusFailed
+			result.Error = err.Error()
+		}
+		results = append(results, result)
+	}
+	return results
+}
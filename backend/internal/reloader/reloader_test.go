@@ -0,0 +1,107 @@
+package reloader
+
+import (
+	"admin_server/backend/internal/config"
+	"admin_server/backend/internal/models"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+type fakeNotifier struct {
+	name string
+	err  error
+}
+
+func (n *fakeNotifier) Name() string   { return n.name }
+func (n *fakeNotifier) Target() string { return "target-" + n.name }
+func (n *fakeNotifier) Notify(ctx context.Context, event models.RuleReloadEvent) error {
+	return n.err
+}
+
+func TestReloadReportsNoSubscribersSeparately(t *testing.T) {
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	m := &Manager{}
+	m.Add(&fakeNotifier{name: "http"})
+	m.Add(&fakeNotifier{name: "redis", err: fmt.Errorf("%w on channel rules", ErrNoSubscribers)})
+	m.Add(&fakeNotifier{name: "rollout", err: errors.New("deployment not found")})
+
+	results := m.Reload(context.Background(), models.RuleReloadEvent{})
+	want := []models.ReloadResult{
+		{Notifier: "http", Target: "target-http", Status: StatusOK},
+		{Notifier: "redis", Target: "target-redis", Status: StatusNoSubscribers},
+		{Notifier: "rollout", Target: "target-rollout", Status: StatusFailed, Error: "deployment not found"},
+	}
+	if len(results) != len(want) {
+		t.Fatalf("results = %+v, want %+v", results, want)
+	}
+	for i := range want {
+		if results[i] != want[i] {
+			t.Fatalf("results[%d] = %+v, want %+v", i, results[i], want[i])
+		}
+	}
+}
+
+func TestHTTPNotifierDoesNotLeakTheURL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	for _, url := range []string{server.URL + "/reload?token=s3cret", "http://127.0.0.1:1/reload?token=s3cret"} {
+		n := NewHTTPNotifier(url, "")
+		err := n.Notify(context.Background(), models.RuleReloadEvent{})
+		if err == nil || strings.Contains(err.Error(), "s3cret") || strings.Contains(n.Target(), "s3cret") {
+			t.Fatalf("target %q, err %v: want a failure that does not contain the URL's secret", n.Target(), err)
+		}
+	}
+}
+
+func TestRolloutNotifiersOnlyRestartTheirRuleset(t *testing.T) {
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	cfg := &config.Config{
+		Namespace:           "security",
+		Rulesets:            []string{"tenant-a=tenants/rules-a", "tenant-b=tenants/rules-b"},
+		RuleReloadNotifiers: []string{"rollout"},
+		RuleReloadRollouts:  []string{"daemonset/rule-engine", "tenant-a=deployment/engine-a"},
+	}
+	m, err := NewManager(cfg, fake.NewClientset(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	targets := func(ruleset string) string {
+		var got []string
+		for _, result := range m.Reload(context.Background(), models.RuleReloadEvent{Ruleset: ruleset}) {
+			got = append(got, result.Target)
+		}
+		return fmt.Sprint(got)
+	}
+	if got := targets("default"); got != "[security/daemonset/rule-engine]" {
+		t.Fatalf("default ruleset restarted %s, want only the global rule engine", got)
+	}
+	if got := targets("tenant-a"); got != "[tenants/deployment/engine-a]" {
+		t.Fatalf("tenant-a restarted %s, want only its own engine in its namespace", got)
+	}
+	if got := targets("tenant-b"); got != "[]" {
+		t.Fatalf("tenant-b declares no rollout but restarted %s", got)
+	}
+
+	cfg.RuleReloadRollouts = []string{"tenant-c=deployment/engine-c"}
+	if _, err := NewManager(cfg, fake.NewClientset(), nil); err == nil || !strings.Contains(err.Error(), "tenant-c") {
+		t.Fatalf("err = %v, want an unknown ruleset error", err)
+	}
+}
@@ -0,0 +1,113 @@
+package services
+
+import (
+	"admin_server/backend/internal/models"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// AlertSubscription receives live alerts until closed. C is closed when the
+// subscriber is dropped for falling behind or after Close.
+type AlertSubscription struct {
+	C <-chan models.Alert
+
+	ch          chan models.Alert
+	filter      AlertFilter
+	broadcaster *AlertBroadcaster
+	once        sync.Once
+}
+
+// Close unregisters the subscription
+func (sub *AlertSubscription) Close() {
+	sub.broadcaster.remove(sub)
+}
+
+// AlertBroadcaster fans newly accepted alerts out to stream subscribers.
+// Publish never blocks: a subscriber whose buffer is full is dropped.
+type AlertBroadcaster struct {
+	mu          sync.RWMutex
+	subscribers map[*AlertSubscription]struct{}
+	bufferSize  int
+}
+
+func NewAlertBroadcaster(bufferSize int) *AlertBroadcaster {
+	if bufferSize <= 0 {
+		bufferSize = 1
+	}
+	return &AlertBroadcaster{
+		subscribers: make(map[*AlertSubscription]struct{}),
+		bufferSize:  bufferSize,
+	}
+}
+
+// Subscribe registers a subscriber for alerts matching filter
+func (b *AlertBroadcaster) Subscribe(filter AlertFilter) *AlertSubscription {
+	ch := make(chan models.Alert, b.bufferSize)
+	sub := &AlertSubscription{
+		C:           ch,
+		ch:          ch,
+		filter:      filter,
+		broadcaster: b,
+	}
+
+	b.mu.Lock()
+	b.subscribers[sub] = struct{}{}
+	b.mu.Unlock()
+	return sub
+}
+
+// Publish delivers alert to every matching subscriber without blocking
+func (b *AlertBroadcaster) Publish(alert models.Alert) {
+	var slow []*AlertSubscription
+
+	b.mu.RLock()
+	for sub := range b.subscribers {
+		if !sub.filter.Matches(alert) {
+			continue
+		}
+		select {
+		case sub.ch <- alert:
+		default:
+			slow = append(slow, sub)
+		}
+	}
+	b.mu.RUnlock()
+
+	for _, sub := range slow {
+		log.Printf("WARN: Dropping slow alert stream subscriber (buffer %d full)", b.bufferSize)
+		b.remove(sub)
+	}
+}
+
+func (b *AlertBroadcaster) remove(sub *AlertSubscription) {
+	b.mu.Lock()
+	delete(b.subscribers, sub)
+	b.mu.Unlock()
+	sub.once.Do(func() { close(sub.ch) })
+}
+
+// AlertEventID builds the SSE event id "<unix ms>-<alert_id>" used for Last-Event-ID resume
+func AlertEventID(alert models.Alert) string {
+	alertTime, err := time.Parse(time.RFC3339, alert.Timestamp)
+	if err != nil {
+		return "0-" + alert.AlertID
+	}
+	return fmt.Sprintf("%d-%s", alertTime.UnixMilli(), alert.AlertID)
+}
+
+// ParseAlertEventID splits an id produced by AlertEventID
+func ParseAlertEventID(id string) (time.Time, string, error) {
+	msStr, alertID, ok := strings.Cut(id, "-")
+	if !ok {
+		return time.Time{}, "", fmt.Errorf("invalid event id %q", id)
+	}
+	ms, err := strconv.ParseInt(msStr, 10, 64)
+	if err != nil {
+		return time.Time{}, "", fmt.Errorf("invalid event id %q: %w", id, err)
+	}
+	return time.UnixMilli(ms).UTC(), alertID, nil
+}
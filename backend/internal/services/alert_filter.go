@@ -0,0 +1,144 @@
+package services
+
+import (
+	"admin_server/backend/internal/models"
+	"cmp"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrInvalidCursor is returned when a pagination cursor cannot be decoded
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// AlertFilter selects which alerts a query or stream subscriber receives.
+// Zero-valued fields match everything.
+type AlertFilter struct {
+	Since      *time.Time // exclusive lower bound
+	Until      *time.Time // inclusive upper bound
+	Severities []string   // any of these (case-insensitive)
+	States     []string   // any of these lifecycle states
+	RuleID     string
+	Namespace  string
+	PodName    string
+	IncidentID string
+	Query      string // case-insensitive substring search inside syscall_log
+}
+
+// Matches reports whether alert passes the filter
+func (f AlertFilter) Matches(alert models.Alert) bool {
+	if f.Since != nil || f.Until != nil {
+		alertTime, err := time.Parse(time.RFC3339, alert.Timestamp)
+		if err != nil {
+			return false
+		}
+		if f.Since != nil && !alertTime.After(*f.Since) {
+			return false
+		}
+		if f.Until != nil && alertTime.After(*f.Until) {
+			return false
+		}
+	}
+	if len(f.Severities) > 0 && !containsFold(f.Severities, alert.Severity) {
+		return false
+	}
+	if len(f.States) > 0 && !containsFold(f.States, alertState(alert)) {
+		return false
+	}
+	if f.RuleID != "" && alert.RuleID != f.RuleID {
+		return false
+	}
+	if f.Namespace != "" && alert.Namespace != f.Namespace {
+		return false
+	}
+	if f.PodName != "" && alert.PodName != f.PodName {
+		return false
+	}
+	if f.IncidentID != "" && alert.IncidentID != f.IncidentID {
+		return false
+	}
+	if f.Query != "" && !syscallLogContains(alert.SyscallLog, f.Query) {
+		return false
+	}
+	return true
+}
+
+// alertState treats alerts stored before lifecycle states existed as open
+func alertState(alert models.Alert) string {
+	if alert.State == "" {
+		return models.AlertStateOpen
+	}
+	return alert.State
+}
+
+func containsFold(values []string, target string) bool {
+	for _, value := range values {
+		if strings.EqualFold(value, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// syscallLogContains searches keys and values of the (arbitrarily nested) syscall_log
+func syscallLogContains(syscallLog map[string]interface{}, query string) bool {
+	if len(syscallLog) == 0 {
+		return false
+	}
+	raw, err := json.Marshal(syscallLog)
+	if err != nil {
+		return false
+	}
+	return strings.Contains(strings.ToLower(string(raw)), strings.ToLower(query))
+}
+
+// alertCursor marks the last alert of a page: its position in the alert index
+type alertCursor struct {
+	TimeMs  int64  `json:"t"`
+	AlertID string `json:"id"`
+}
+
+// alertPosition returns where alert sits in the alert index (its score is the unix ms
+// of its timestamp)
+func alertPosition(alert models.Alert) alertCursor {
+	var ms int64
+	if alertTime, err := time.Parse(time.RFC3339, alert.Timestamp); err == nil {
+		ms = alertTime.UnixMilli()
+	}
+	return alertCursor{TimeMs: ms, AlertID: alert.AlertID}
+}
+
+// compareAlertPositions orders positions like the alert index: by time, then by alert_id
+func compareAlertPositions(a, b alertCursor) int {
+	if c := cmp.Compare(a.TimeMs, b.TimeMs); c != 0 {
+		return c
+	}
+	return strings.Compare(a.AlertID, b.AlertID)
+}
+
+func encodeAlertCursor(alert models.Alert) string {
+	raw, _ := json.Marshal(alertPosition(alert))
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func decodeAlertCursor(cursor string) (*alertCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+	var c alertCursor
+	if err := json.Unmarshal(raw, &c); err != nil || c.AlertID == "" {
+		return nil, ErrInvalidCursor
+	}
+	return &c, nil
+}
+
+// timeOnly reports whether the filter selects by time range alone, so the store can
+// count its matches without reading them
+func (f AlertFilter) timeOnly() bool {
+	return len(f.Severities) == 0 && len(f.States) == 0 && f.RuleID == "" && f.Namespace == "" &&
+		f.PodName == "" && f.IncidentID == "" && f.Query == ""
+}
@@ -3,82 +3,171 @@ package services
 import (
 	"admin_server/backend/internal/config"
 	"admin_server/backend/internal/models"
+	"admin_server/backend/internal/notifier"
+	"context"
+	"errors"
+	"fmt"
 	"log"
-	"sort"
+	"slices"
 	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
 )
 
+// ErrInvalidAlertUpdate is returned for malformed updates or disallowed state transitions
+var ErrInvalidAlertUpdate = errors.New("invalid alert update")
+
+// ErrInvalidAlert is returned for an incoming alert that can never be stored, from
+// either the HTTP webhook or the Kafka consumer
+var ErrInvalidAlert = errors.New("invalid alert")
+
+// alertTransitions lists the states each state may move to
+var alertTransitions = map[string][]string{
+	models.AlertStateOpen:          {models.AlertStateAcknowledged, models.AlertStateResolved, models.AlertStateFalsePositive},
+	models.AlertStateAcknowledged:  {models.AlertStateOpen, models.AlertStateResolved, models.AlertStateFalsePositive},
+	models.AlertStateResolved:      {models.AlertStateOpen},
+	models.AlertStateFalsePositive: {models.AlertStateOpen},
+}
+
 // AlertService handles alert-related operations
 type AlertService struct {
-	cfg *config.Config
-	// In-memory storage for alerts (TODO: Replace with Redis)
-	alerts []models.Alert
-	// TODO: Add Redis client when implementing actual Redis integration
-	// redisClient *redis.Client
+	cfg         *config.Config
+	store       alertStore
+	notifier    *notifier.Dispatcher
+	broadcaster *AlertBroadcaster
+	incidents   *IncidentService
+	silences    *SilenceService
 }
 
-func NewAlertService(cfg *config.Config) *AlertService {
+// NewAlertService creates an AlertService backed by Redis, or by process memory
+// when ALERT_STORE=memory. New alerts are grouped into incidents, checked against
+// silences, and (unless silenced) handed to notifier for outbound delivery.
+func NewAlertService(cfg *config.Config, redisClient *redis.Client, notifier *notifier.Dispatcher, incidents *IncidentService, silences *SilenceService) *AlertService {
+	var store alertStore
+	if cfg.AlertStore == "memory" {
+		log.Println("Using in-memory alert store (alerts are lost on restart)")
+		store = newMemoryAlertStore()
+	} else {
+		store = newRedisAlertStore(redisClient, cfg.AlertRedisKey)
+	}
+
 	return &AlertService{
-		cfg:    cfg,
-		alerts: make([]models.Alert, 0),
+		cfg:         cfg,
+		store:       store,
+		notifier:    notifier,
+		broadcaster: NewAlertBroadcaster(cfg.AlertStreamBuffer),
+		incidents:   incidents,
+		silences:    silences,
 	}
 }
 
-// GetAlerts retrieves alerts with optional filtering
-func (s *AlertService) GetAlerts(limit int, since *time.Time) (*models.AlertsResponse, error) {
-	// TODO: Implement actual Redis retrieval
-	log.Println("Getting alerts (mock implementation)")
-
-	// Filter alerts
-	filteredAlerts := s.alerts
-
-	// Filter by time if since is provided
-	if since != nil {
-		filtered := make([]models.Alert, 0)
-		for _, alert := range filteredAlerts {
-			alertTime, err := time.Parse(time.RFC3339, alert.Timestamp)
-			if err == nil && alertTime.After(*since) {
-				filtered = append(filtered, alert)
+// alertScanBatch is how many alerts GetAlerts reads from the store at a time while
+// filling a page
+const alertScanBatch = 200
+
+// GetAlerts returns one page (newest first) of alerts matching filter.
+// cursor is the NextCursor of the previous page; empty starts from the newest alert.
+// The store is read in batches from the cursor until the page is full, so a page costs
+// about limit alerts (more when the filter is selective) rather than every stored alert.
+// Total counts every match of filter, not just the alerts after the cursor.
+func (s *AlertService) GetAlerts(filter AlertFilter, limit int, cursor string) (*models.AlertsResponse, error) {
+	log.Println("Getting alerts from alert store")
+
+	var after *alertCursor
+	if cursor != "" {
+		var err error
+		if after, err = decodeAlertCursor(cursor); err != nil {
+			return nil, err
+		}
+	}
+
+	ctx := context.Background()
+	page := make([]models.Alert, 0)
+	more := false
+	batchSize := 0 // limit가 없으면 한 번에 모두 읽음
+	if limit > 0 {
+		batchSize = max(limit+1, alertScanBatch)
+	}
+scan:
+	for {
+		batch, err := s.store.Range(ctx, filter.Since, filter.Until, after, batchSize)
+		if err != nil {
+			log.Printf("ERROR: Failed to retrieve alerts: %v", err)
+			return nil, err
+		}
+		for _, alert := range batch {
+			if !filter.Matches(alert) {
+				continue
 			}
+			if limit > 0 && len(page) == limit {
+				more = true
+				break scan
+			}
+			page = append(page, alert)
+		}
+		if batchSize <= 0 || len(batch) < batchSize {
+			break
 		}
-		filteredAlerts = filtered
+		position := alertPosition(batch[len(batch)-1])
+		after = &position
 	}
 
-	// Sort by timestamp (newest first)
-	sort.Slice(filteredAlerts, func(i, j int) bool {
-		timeI, _ := time.Parse(time.RFC3339, filteredAlerts[i].Timestamp)
-		timeJ, _ := time.Parse(time.RFC3339, filteredAlerts[j].Timestamp)
-		return timeI.After(timeJ)
-	})
+	response := &models.AlertsResponse{Alerts: page}
+	if more {
+		response.NextCursor = encodeAlertCursor(page[len(page)-1])
+	}
+	total, err := s.countAlerts(ctx, filter)
+	if err != nil {
+		log.Printf("ERROR: Failed to count alerts: %v", err)
+		return nil, err
+	}
+	response.Total = total
+	return response, nil
+}
+
+// countAlerts returns the number of alerts matching filter. A time range alone is
+// counted by the store; other filters take a count-only pass over the range, which
+// reads the alerts in batches but keeps none of them.
+func (s *AlertService) countAlerts(ctx context.Context, filter AlertFilter) (int, error) {
+	if filter.timeOnly() {
+		return s.store.Count(ctx, filter.Since, filter.Until)
+	}
 
-	// Apply limit
-	if limit > 0 && limit < len(filteredAlerts) {
-		filteredAlerts = filteredAlerts[:limit]
-	}
-
-	// Actual implementation will look like:
-	// redisClient := redis.NewClient(&redis.Options{
-	//     Addr:     fmt.Sprintf("%s:%s", s.cfg.RedisHost, s.cfg.RedisPort),
-	//     Password: s.cfg.RedisPassword,
-	//     DB:       s.cfg.RedisDB,
-	// })
-	//
-	// // Get alerts from Redis sorted set or list
-	// alerts, err := redisClient.ZRangeByScore(context.TODO(), "alerts", &redis.ZRangeBy{
-	//     Min: sinceTimestamp,
-	//     Max: "+inf",
-	// }).Result()
-
-	return &models.AlertsResponse{
-		Alerts: filteredAlerts,
-	}, nil
+	total := 0
+	var after *alertCursor
+	for {
+		batch, err := s.store.Range(ctx, filter.Since, filter.Until, after, alertScanBatch)
+		if err != nil {
+			return 0, err
+		}
+		for _, alert := range batch {
+			if filter.Matches(alert) {
+				total++
+			}
+		}
+		if len(batch) < alertScanBatch {
+			return total, nil
+		}
+		position := alertPosition(batch[len(batch)-1])
+		after = &position
+	}
 }
 
-// ReceiveWebhook receives an alert from the rule engine via webhook
+// ValidateWebhookAlert checks an incoming alert the same way for both ingestion paths
+func ValidateWebhookAlert(alert *models.WebhookAlert) error {
+	if alert.RuleID == "" {
+		return fmt.Errorf("%w: rule_id is required", ErrInvalidAlert)
+	}
+	return nil
+}
+
+// ReceiveWebhook receives an alert from the rule engine via webhook or the Kafka consumer
 func (s *AlertService) ReceiveWebhook(alert *models.WebhookAlert) error {
-	// TODO: This will be called by Kafka consumer
-	// For now, store in memory
 	log.Printf("Receiving webhook alert: %s", alert.AlertID)
+	if err := ValidateWebhookAlert(alert); err != nil {
+		return err
+	}
 
 	// Convert WebhookAlert to Alert
 	newAlert := models.Alert{
@@ -90,23 +179,202 @@ func (s *AlertService) ReceiveWebhook(alert *models.WebhookAlert) error {
 		PodName:         alert.PodName,
 		Namespace:       alert.Namespace,
 		SyscallLog:      alert.SyscallLog,
+		State:           models.AlertStateOpen,
+	}
+	if newAlert.AlertID == "" {
+		newAlert.AlertID = uuid.NewString()
+	}
+
+	// 타임스탬프가 없거나 파싱할 수 없으면 수신 시각을 score로 사용
+	now := time.Now().UTC()
+	alertTime, err := time.Parse(time.RFC3339, newAlert.Timestamp)
+	if err != nil {
+		if newAlert.Timestamp != "" {
+			log.Printf("WARN: Alert %s has invalid timestamp %q, using receive time", newAlert.AlertID, newAlert.Timestamp)
+		}
+		alertTime = now
+		newAlert.Timestamp = now.Format(time.RFC3339)
+	}
+
+	// 사일런스에 걸린 알림도 저장은 하되 표시만 해두고 알림 전송/스트리밍은 생략
+	if s.silences != nil {
+		if silence, err := s.silences.Match(newAlert, alertTime); err != nil {
+			log.Printf("WARN: Failed to check silences for alert %s: %v", newAlert.AlertID, err)
+		} else if silence != nil {
+			newAlert.Silenced = true
+			newAlert.SilenceID = silence.SilenceID
+		}
+	}
+
+	ctx := context.Background()
+	err = s.store.Add(ctx, newAlert, alertTime)
+	if errors.Is(err, ErrAlertExists) {
+		// 재전송된 알림은 저장된 상태(담당자, 코멘트 등)를 유지하고 인시던트 집계와 알림 전송을 생략
+		log.Printf("Alert %s was already received, ignoring the duplicate", newAlert.AlertID)
+		return nil
+	}
+	if err != nil {
+		log.Printf("ERROR: Failed to store alert %s: %v", newAlert.AlertID, err)
+		return err
 	}
 
-	s.alerts = append(s.alerts, newAlert)
+	// 저장에 성공한 알림만 인시던트에 집계 (저장 실패 후 재전송 시 중복 집계 방지)
+	// 그룹핑 실패는 알림 수신을 막지 않도록 경고만 남김
+	if s.incidents != nil {
+		if incident, err := s.incidents.Record(newAlert, alertTime); err != nil {
+			log.Printf("WARN: Failed to group alert %s into an incident: %v", newAlert.AlertID, err)
+		} else {
+			newAlert.IncidentID = incident.IncidentID
+			incidentID := incident.IncidentID
+			if _, err := s.store.Update(ctx, newAlert.AlertID, func(alert *models.Alert) error {
+				alert.IncidentID = incidentID
+				return nil
+			}); err != nil {
+				log.Printf("WARN: Failed to link alert %s to incident %s: %v", newAlert.AlertID, incidentID, err)
+			}
+		}
+	}
 
-	// TODO: Store in Redis
-	// redisClient := redis.NewClient(&redis.Options{
-	//     Addr:     fmt.Sprintf("%s:%s", s.cfg.RedisHost, s.cfg.RedisPort),
-	//     Password: s.cfg.RedisPassword,
-	//     DB:       s.cfg.RedisDB,
-	// })
-	//
-	// alertJSON, _ := json.Marshal(newAlert)
-	// err := redisClient.LPush(context.TODO(), "alerts", alertJSON).Err()
-	// or use sorted set with timestamp as score
+	// Retention trimming is best effort; the alert itself is already stored
+	var before time.Time
+	if s.cfg.AlertRetention > 0 {
+		before = now.Add(-s.cfg.AlertRetention)
+	}
+	if err := s.store.Trim(ctx, before, s.cfg.AlertMaxCount); err != nil {
+		log.Printf("WARN: Failed to trim old alerts: %v", err)
+	} else if s.incidents != nil {
+		s.trimIncidents(ctx, now)
+	}
 
-	// TODO: Send webhook notification (Slack, etc.)
-	// sendWebhookNotification(newAlert)
+	if newAlert.Silenced {
+		log.Printf("Alert %s silenced by %s", newAlert.AlertID, newAlert.SilenceID)
+		return nil
+	}
+
+	// Slack/webhook/email 알림은 비동기로 전송 (전송 실패가 수신을 막지 않음)
+	s.notifier.Notify(newAlert)
+	s.broadcaster.Publish(newAlert)
 
 	return nil
 }
+
+// trimIncidents drops incidents whose alerts have all been trimmed: an incident's
+// last_seen is the timestamp of its newest alert, so everything last seen before the
+// oldest remaining alert has none left
+func (s *AlertService) trimIncidents(ctx context.Context, now time.Time) {
+	oldest, err := s.store.Oldest(ctx)
+	if err != nil {
+		log.Printf("WARN: Failed to trim old incidents: %v", err)
+		return
+	}
+	before := now
+	if oldest != nil {
+		before = *oldest
+	}
+	if err := s.incidents.Trim(before); err != nil {
+		log.Printf("WARN: Failed to trim old incidents: %v", err)
+	}
+}
+
+// SubscribeAlerts registers a live alert subscriber and returns the stored alerts
+// to replay first, oldest first. When lastEventID is set (SSE Last-Event-ID) replay
+// resumes strictly after that event's (timestamp, alert_id) position in the alert
+// index and overrides filter.Since.
+func (s *AlertService) SubscribeAlerts(filter AlertFilter, limit int, lastEventID string) ([]models.Alert, *AlertSubscription, error) {
+	var last *alertCursor
+	if lastEventID != "" {
+		lastTime, alertID, err := ParseAlertEventID(lastEventID)
+		if err != nil {
+			return nil, nil, err
+		}
+		last = &alertCursor{TimeMs: lastTime.UnixMilli(), AlertID: alertID}
+		// 같은 시각의 알림도 읽도록 1ms 앞에서부터 읽고, 아래에서 마지막 이벤트 이후 위치만 남김
+		since := lastTime.Add(-time.Millisecond)
+		filter.Since = &since
+	}
+
+	// Subscribe before reading the store so nothing accepted in between is lost;
+	// the stream handler drops live duplicates of replayed alerts.
+	sub := s.broadcaster.Subscribe(filter)
+
+	replay := make([]models.Alert, 0)
+	if filter.Since != nil {
+		stored, err := s.store.Range(context.Background(), filter.Since, filter.Until, nil, 0)
+		if err != nil {
+			sub.Close()
+			return nil, nil, err
+		}
+		// stored is newest first: keep the newest `limit` matches, then emit oldest first
+		for _, alert := range stored {
+			if limit > 0 && len(replay) >= limit {
+				break
+			}
+			if last != nil && compareAlertPositions(alertPosition(alert), *last) <= 0 {
+				continue
+			}
+			if !filter.Matches(alert) {
+				continue
+			}
+			replay = append(replay, alert)
+		}
+		slices.Reverse(replay)
+	}
+
+	return replay, sub, nil
+}
+
+// GetAlert returns a single alert by ID
+func (s *AlertService) GetAlert(id string) (*models.Alert, error) {
+	return s.store.Get(context.Background(), id)
+}
+
+// UpdateAlert changes an alert's state and/or assignee, recording every state transition
+func (s *AlertService) UpdateAlert(id string, req *models.UpdateAlertRequest) (*models.Alert, error) {
+	if req.State == nil && req.Assignee == nil {
+		return nil, fmt.Errorf("%w: state or assignee is required", ErrInvalidAlertUpdate)
+	}
+	actor := req.Actor
+	if actor == "" {
+		actor = "anonymous"
+	}
+
+	log.Printf("Updating alert %s by %s", id, actor)
+	return s.store.Update(context.Background(), id, func(alert *models.Alert) error {
+		if req.State != nil && *req.State != alert.State {
+			if !slices.Contains(alertTransitions[alert.State], *req.State) {
+				return fmt.Errorf("%w: cannot move alert from %q to %q", ErrInvalidAlertUpdate, alert.State, *req.State)
+			}
+			alert.History = append(alert.History, models.AlertTransition{
+				From:      alert.State,
+				To:        *req.State,
+				Actor:     actor,
+				Timestamp: time.Now().UTC().Format(time.RFC3339),
+			})
+			alert.State = *req.State
+		}
+		if req.Assignee != nil {
+			alert.Assignee = *req.Assignee
+		}
+		return nil
+	})
+}
+
+// AddAlertComment appends a timestamped comment to an alert's thread
+func (s *AlertService) AddAlertComment(id string, req *models.AddAlertCommentRequest) (*models.Alert, error) {
+	if req.Text == "" {
+		return nil, fmt.Errorf("%w: text is required", ErrInvalidAlertUpdate)
+	}
+	author := req.Author
+	if author == "" {
+		author = "anonymous"
+	}
+
+	return s.store.Update(context.Background(), id, func(alert *models.Alert) error {
+		alert.Comments = append(alert.Comments, models.AlertComment{
+			Author:    author,
+			Text:      req.Text,
+			Timestamp: time.Now().UTC().Format(time.RFC3339),
+		})
+		return nil
+	})
+}
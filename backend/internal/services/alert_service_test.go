@@ -0,0 +1,193 @@
+package services
+
+import (
+	"admin_server/backend/internal/config"
+	"admin_server/backend/internal/models"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"sync"
+	"testing"
+	"time"
+)
+
+func newTestAlertService(t *testing.T) *AlertService {
+	t.Helper()
+	output := log.Writer()
+	log.SetOutput(io.Discard)
+	t.Cleanup(func() { log.SetOutput(output) })
+
+	cfg := &config.Config{
+		AlertStore:        "memory",
+		AlertStreamBuffer: 1024,
+		IncidentWindow:    time.Minute,
+	}
+	return NewAlertService(cfg, nil, nil, NewIncidentService(cfg, nil), nil)
+}
+
+// Run with -race: webhook deliveries (including redeliveries of the same alert_id),
+// page reads and stream subscribers all share the store, incidents and broadcaster.
+func TestReceiveWebhookConcurrentWithGetAlerts(t *testing.T) {
+	service := newTestAlertService(t)
+	const writers, perWriter, readers = 8, 50, 4
+
+	_, sub, err := service.SubscribeAlerts(AlertFilter{}, 0, "")
+	if err != nil {
+		t.Fatalf("SubscribeAlerts: %v", err)
+	}
+	defer sub.Close()
+
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	done := make(chan struct{})
+	var writeWG, readWG sync.WaitGroup
+	for w := 0; w < writers; w++ {
+		writeWG.Add(1)
+		go func(w int) {
+			defer writeWG.Done()
+			for i := 0; i < perWriter; i++ {
+				alert := &models.WebhookAlert{
+					AlertID: fmt.Sprintf("w%d-%03d", w, i),
+					// 여러 알림이 같은 초에 몰리도록 해서 동순위 커서 처리도 함께 검사
+					Timestamp: base.Add(time.Duration(i/5) * time.Second).Format(time.RFC3339),
+					RuleID:    fmt.Sprintf("R%d", i%3),
+					Severity:  "high",
+					Namespace: "default",
+					PodName:   fmt.Sprintf("pod-%d", w),
+				}
+				if err := service.ReceiveWebhook(alert); err != nil {
+					t.Errorf("ReceiveWebhook(%s): %v", alert.AlertID, err)
+				}
+				// 재전송된 같은 알림은 무시되어야 함
+				if err := service.ReceiveWebhook(alert); err != nil {
+					t.Errorf("ReceiveWebhook(%s) redelivery: %v", alert.AlertID, err)
+				}
+			}
+		}(w)
+	}
+	for r := 0; r < readers; r++ {
+		readWG.Add(1)
+		go func(r int) {
+			defer readWG.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				if _, err := readAllPages(service, AlertFilter{}, 7); err != nil {
+					t.Errorf("reader %d: %v", r, err)
+					return
+				}
+				if _, err := service.GetAlerts(AlertFilter{RuleID: "R1"}, 10, ""); err != nil {
+					t.Errorf("reader %d: %v", r, err)
+					return
+				}
+			}
+		}(r)
+	}
+	writeWG.Wait()
+	close(done)
+	readWG.Wait()
+
+	all, err := readAllPages(service, AlertFilter{}, 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != writers*perWriter {
+		t.Fatalf("paged through %d alerts, want %d", len(all), writers*perWriter)
+	}
+	seen := make(map[string]bool, len(all))
+	for i, alert := range all {
+		if seen[alert.AlertID] {
+			t.Fatalf("alert %s returned twice", alert.AlertID)
+		}
+		seen[alert.AlertID] = true
+		if alert.IncidentID == "" {
+			t.Fatalf("alert %s was not linked to an incident", alert.AlertID)
+		}
+		if i > 0 && compareAlertPositions(alertPosition(all[i-1]), alertPosition(alert)) <= 0 {
+			t.Fatalf("alerts %s and %s are out of order", all[i-1].AlertID, alert.AlertID)
+		}
+	}
+
+	response, err := service.GetAlerts(AlertFilter{}, 1, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if response.Total != writers*perWriter {
+		t.Fatalf("total = %v, want %d", response.Total, writers*perWriter)
+	}
+	if streamed := len(sub.C); streamed != writers*perWriter {
+		t.Fatalf("streamed %d alerts, want %d (redeliveries must not be broadcast)", streamed, writers*perWriter)
+	}
+}
+
+// readAllPages follows NextCursor until the last page
+func readAllPages(service *AlertService, filter AlertFilter, limit int) ([]models.Alert, error) {
+	var all []models.Alert
+	cursor := ""
+	for {
+		response, err := service.GetAlerts(filter, limit, cursor)
+		if err != nil {
+			return nil, err
+		}
+		if len(response.Alerts) > limit {
+			return nil, fmt.Errorf("page of %d alerts exceeds limit %d", len(response.Alerts), limit)
+		}
+		all = append(all, response.Alerts...)
+		if response.NextCursor == "" {
+			return all, nil
+		}
+		cursor = response.NextCursor
+	}
+}
+
+func TestGetAlertsTotalCountsFilteredMatches(t *testing.T) {
+	service := newTestAlertService(t)
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	// 한 배치(alertScanBatch)를 넘겨서 count-only 스캔이 여러 배치를 읽는지 검사
+	for i := 0; i < alertScanBatch+50; i++ {
+		if err := service.ReceiveWebhook(&models.WebhookAlert{
+			AlertID:   fmt.Sprintf("a%03d", i),
+			Timestamp: base.Add(time.Duration(i) * time.Second).Format(time.RFC3339),
+			RuleID:    fmt.Sprintf("R%d", i%3),
+			Severity:  "high",
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want := 0
+	for i := 0; i < alertScanBatch+50; i++ {
+		if i%3 == 1 {
+			want++
+		}
+	}
+	first, err := service.GetAlerts(AlertFilter{RuleID: "R1"}, 10, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.Total != want || len(first.Alerts) != 10 {
+		t.Fatalf("total = %d with %d alerts, want %d with 10", first.Total, len(first.Alerts), want)
+	}
+	// 다음 페이지도 커서와 관계없이 전체 일치 수를 반환
+	second, err := service.GetAlerts(AlertFilter{RuleID: "R1"}, 10, first.NextCursor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second.Total != want {
+		t.Fatalf("second page total = %d, want %d", second.Total, want)
+	}
+}
+
+func TestReceiveWebhookRejectsAlertsWithoutRuleID(t *testing.T) {
+	service := newTestAlertService(t)
+	err := service.ReceiveWebhook(&models.WebhookAlert{AlertID: "a1", Severity: "high"})
+	if !errors.Is(err, ErrInvalidAlert) {
+		t.Fatalf("err = %v, want ErrInvalidAlert", err)
+	}
+	if response, _ := service.GetAlerts(AlertFilter{}, 0, ""); response.Total != 0 {
+		t.Fatalf("stored %d alerts without rule_id", response.Total)
+	}
+}
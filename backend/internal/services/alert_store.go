@@ -0,0 +1,468 @@
+package services
+
+import (
+	"admin_server/backend/internal/models"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ErrAlertNotFound is returned when no alert has the requested ID
+var ErrAlertNotFound = errors.New("alert not found")
+
+func (m *memoryAlertStore) Oldest(ctx context.Context) (*time.Time, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var oldest *time.Time
+	for _, alert := range m.alerts {
+		alertTime, err := time.Parse(time.RFC3339, alert.Timestamp)
+		if err == nil && (oldest == nil || alertTime.Before(*oldest)) {
+			oldest = &alertTime
+		}
+	}
+	return oldest, nil
+}
+
+// ErrAlertExists is returned by Add for an alert_id that is already stored, e.g. a
+// Kafka redelivery or a retried webhook
+var ErrAlertExists = errors.New("alert already exists")
+
+// maxUpdateRetries bounds optimistic (WATCH) retries of a Redis alert update
+const maxUpdateRetries = 10
+
+// alertStore persists alerts ordered by their timestamp
+type alertStore interface {
+	// Add stores an alert scored by ts, or returns ErrAlertExists (leaving the stored
+	// alert untouched) when its alert_id is already stored
+	Add(ctx context.Context, alert models.Alert, ts time.Time) error
+	// Range returns alerts with since < timestamp <= until (either bound may be nil),
+	// newest first (ties by alert_id descending). When after is set only alerts that come
+	// after that position in this order are returned. A limit <= 0 returns every match.
+	Range(ctx context.Context, since, until *time.Time, after *alertCursor, limit int) ([]models.Alert, error)
+	// Count returns the number of alerts with since < timestamp <= until
+	Count(ctx context.Context, since, until *time.Time) (int, error)
+	// Get returns a single alert by ID or ErrAlertNotFound
+	Get(ctx context.Context, id string) (*models.Alert, error)
+	// Update atomically applies fn to the stored alert and returns the result.
+	// Nothing is written when fn returns an error.
+	Update(ctx context.Context, id string, fn func(alert *models.Alert) error) (*models.Alert, error)
+	// Trim drops alerts older than before (ignored when zero) and keeps at most maxCount (ignored when <= 0)
+	Trim(ctx context.Context, before time.Time, maxCount int) error
+	// Oldest returns the timestamp of the oldest stored alert, or nil when there is none
+	Oldest(ctx context.Context) (*time.Time, error)
+}
+
+// redisAlertStore keeps alert IDs in a sorted set scored by timestamp (unix ms) and
+// each alert body as JSON under its own key, so an update only watches its own alert.
+type redisAlertStore struct {
+	client    *redis.Client
+	indexKey  string
+	keyPrefix string
+}
+
+func newRedisAlertStore(client *redis.Client, prefix string) *redisAlertStore {
+	return &redisAlertStore{
+		client:    client,
+		indexKey:  prefix + ":index",
+		keyPrefix: prefix + ":alert:",
+	}
+}
+
+func (r *redisAlertStore) alertKey(id string) string {
+	return r.keyPrefix + id
+}
+
+// addAlertScript stores an alert only if its ID is new, in one atomic step.
+// KEYS: alert key, index; ARGV: alert JSON, score, alert ID
+var addAlertScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 1 then
+	return 0
+end
+redis.call('SET', KEYS[1], ARGV[1])
+redis.call('ZADD', KEYS[2], ARGV[2], ARGV[3])
+return 1
+`)
+
+func (r *redisAlertStore) Add(ctx context.Context, alert models.Alert, ts time.Time) error {
+	alertJSON, err := json.Marshal(alert)
+	if err != nil {
+		return fmt.Errorf("failed to marshal alert: %w", err)
+	}
+
+	added, err := addAlertScript.Run(ctx, r.client,
+		[]string{r.alertKey(alert.AlertID), r.indexKey},
+		alertJSON, ts.UnixMilli(), alert.AlertID).Int()
+	if err != nil {
+		return fmt.Errorf("failed to store alert in Redis: %w", err)
+	}
+	if added == 0 {
+		return fmt.Errorf("%w: %s", ErrAlertExists, alert.AlertID)
+	}
+	return nil
+}
+
+// scoreBounds converts since/until to ZRANGEBYSCORE bounds
+func scoreBounds(since, until *time.Time) (string, string) {
+	min, max := "-inf", "+inf"
+	if since != nil {
+		// '(' makes the bound exclusive, matching the "after since" semantics
+		min = "(" + strconv.FormatInt(since.UnixMilli(), 10)
+	}
+	if until != nil {
+		max = strconv.FormatInt(until.UnixMilli(), 10)
+	}
+	return min, max
+}
+
+func (r *redisAlertStore) Range(ctx context.Context, since, until *time.Time, after *alertCursor, limit int) ([]models.Alert, error) {
+	opt := &redis.ZRangeBy{}
+	opt.Min, opt.Max = scoreBounds(since, until)
+	if after != nil && (until == nil || after.TimeMs < until.UnixMilli()) {
+		// 커서와 같은 시각의 알림은 아래에서 alert_id로 건너뜀
+		opt.Max = strconv.FormatInt(after.TimeMs, 10)
+	}
+	if limit > 0 {
+		opt.Count = int64(limit)
+	}
+
+	// 커서와 시각이 같은 알림 중 이미 반환한 것을 건너뛰느라 limit개가 안 되면 다음 구간을 더 읽음
+	ids := make([]string, 0, max(limit, 0))
+	for {
+		entries, err := r.client.ZRevRangeByScoreWithScores(ctx, r.indexKey, opt).Result()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read alert index from Redis: %w", err)
+		}
+		for _, entry := range entries {
+			id, _ := entry.Member.(string)
+			if after != nil && int64(entry.Score) == after.TimeMs && id >= after.AlertID {
+				continue
+			}
+			ids = append(ids, id)
+		}
+		if limit <= 0 || len(ids) >= limit || int64(len(entries)) < opt.Count {
+			break
+		}
+		opt.Offset += int64(len(entries))
+	}
+	if limit > 0 && len(ids) > limit {
+		ids = ids[:limit]
+	}
+	return r.load(ctx, ids)
+}
+
+func (r *redisAlertStore) Count(ctx context.Context, since, until *time.Time) (int, error) {
+	min, max := scoreBounds(since, until)
+	count, err := r.client.ZCount(ctx, r.indexKey, min, max).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to count alerts in Redis: %w", err)
+	}
+	return int(count), nil
+}
+
+// load fetches alert bodies for ids, preserving order and skipping missing entries
+func (r *redisAlertStore) load(ctx context.Context, ids []string) ([]models.Alert, error) {
+	alerts := make([]models.Alert, 0, len(ids))
+	if len(ids) == 0 {
+		return alerts, nil
+	}
+
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = r.alertKey(id)
+	}
+	values, err := r.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read alerts from Redis: %w", err)
+	}
+
+	for i, value := range values {
+		raw, ok := value.(string)
+		if !ok {
+			continue
+		}
+		alert, err := decodeStoredAlert(raw)
+		if err != nil {
+			return nil, fmt.Errorf("failed to unmarshal alert %s: %w", ids[i], err)
+		}
+		alerts = append(alerts, *alert)
+	}
+	return alerts, nil
+}
+
+// decodeStoredAlert unmarshals an alert, defaulting alerts stored before lifecycle states existed to open
+func decodeStoredAlert(raw string) (*models.Alert, error) {
+	var alert models.Alert
+	if err := json.Unmarshal([]byte(raw), &alert); err != nil {
+		return nil, err
+	}
+	if alert.State == "" {
+		alert.State = models.AlertStateOpen
+	}
+	return &alert, nil
+}
+
+func (r *redisAlertStore) Get(ctx context.Context, id string) (*models.Alert, error) {
+	raw, err := r.getRaw(ctx, r.client, id)
+	if err != nil {
+		return nil, err
+	}
+	alert, err := decodeStoredAlert(raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal alert %s: %w", id, err)
+	}
+	return alert, nil
+}
+
+// getRaw reads an alert's JSON from its own key
+func (r *redisAlertStore) getRaw(ctx context.Context, client redis.Cmdable, id string) (string, error) {
+	raw, err := client.Get(ctx, r.alertKey(id)).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", ErrAlertNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read alert from Redis: %w", err)
+	}
+	return raw, nil
+}
+
+func (r *redisAlertStore) Update(ctx context.Context, id string, fn func(alert *models.Alert) error) (*models.Alert, error) {
+	var updated *models.Alert
+	key := r.alertKey(id)
+
+	// WATCH only this alert's key so a concurrent update or trim of it aborts the
+	// transaction, while other alerts arriving in the meantime do not
+	txf := func(tx *redis.Tx) error {
+		raw, err := r.getRaw(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		alert, err := decodeStoredAlert(raw)
+		if err != nil {
+			return fmt.Errorf("failed to unmarshal alert %s: %w", id, err)
+		}
+		if err := fn(alert); err != nil {
+			return err
+		}
+		alertJSON, err := json.Marshal(alert)
+		if err != nil {
+			return fmt.Errorf("failed to marshal alert: %w", err)
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Set(ctx, key, alertJSON, 0)
+			return nil
+		})
+		updated = alert
+		return err
+	}
+
+	for i := 0; i < maxUpdateRetries; i++ {
+		err := r.client.Watch(ctx, txf, key)
+		if errors.Is(err, redis.TxFailedErr) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return updated, nil
+	}
+	return nil, fmt.Errorf("failed to update alert %s: too many concurrent modifications", id)
+}
+
+func (r *redisAlertStore) Trim(ctx context.Context, before time.Time, maxCount int) error {
+	var expired []string
+
+	if !before.IsZero() {
+		ids, err := r.client.ZRangeByScore(ctx, r.indexKey, &redis.ZRangeBy{
+			Min: "-inf",
+			Max: "(" + strconv.FormatInt(before.UnixMilli(), 10),
+		}).Result()
+		if err != nil {
+			return fmt.Errorf("failed to read expired alerts: %w", err)
+		}
+		expired = append(expired, ids...)
+	}
+
+	if maxCount > 0 {
+		// Everything except the newest maxCount entries
+		ids, err := r.client.ZRange(ctx, r.indexKey, 0, int64(-maxCount-1)).Result()
+		if err != nil {
+			return fmt.Errorf("failed to read overflow alerts: %w", err)
+		}
+		expired = append(expired, ids...)
+	}
+
+	if len(expired) == 0 {
+		return nil
+	}
+
+	members := make([]interface{}, len(expired))
+	keys := make([]string, len(expired))
+	for i, id := range expired {
+		members[i] = id
+		keys[i] = r.alertKey(id)
+	}
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZRem(ctx, r.indexKey, members...)
+		pipe.Del(ctx, keys...)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to trim alerts: %w", err)
+	}
+	return nil
+}
+
+func (r *redisAlertStore) Oldest(ctx context.Context) (*time.Time, error) {
+	oldest, err := r.client.ZRangeWithScores(ctx, r.indexKey, 0, 0).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read oldest alert: %w", err)
+	}
+	if len(oldest) == 0 {
+		return nil, nil
+	}
+	ts := time.UnixMilli(int64(oldest[0].Score)).UTC()
+	return &ts, nil
+}
+
+// memoryAlertStore keeps alerts in process memory (local development only).
+// Gin serves each request on its own goroutine, so every access goes through mu.
+type memoryAlertStore struct {
+	mu     sync.RWMutex
+	alerts []models.Alert
+}
+
+func newMemoryAlertStore() *memoryAlertStore {
+	return &memoryAlertStore{
+		alerts: make([]models.Alert, 0),
+	}
+}
+
+func (m *memoryAlertStore) Add(ctx context.Context, alert models.Alert, ts time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i := range m.alerts {
+		if m.alerts[i].AlertID == alert.AlertID {
+			return fmt.Errorf("%w: %s", ErrAlertExists, alert.AlertID)
+		}
+	}
+	m.alerts = append(m.alerts, alert)
+	return nil
+}
+
+func (m *memoryAlertStore) Range(ctx context.Context, since, until *time.Time, after *alertCursor, limit int) ([]models.Alert, error) {
+	timeFilter := AlertFilter{Since: since, Until: until}
+
+	m.mu.RLock()
+	// Copy under the lock so sorting below never reorders the stored slice
+	filteredAlerts := make([]models.Alert, 0, len(m.alerts))
+	for _, alert := range m.alerts {
+		if !timeFilter.Matches(alert) {
+			continue
+		}
+		if after != nil && compareAlertPositions(alertPosition(alert), *after) >= 0 {
+			continue
+		}
+		filteredAlerts = append(filteredAlerts, alert)
+	}
+	m.mu.RUnlock()
+
+	// Sort by timestamp (newest first), ties by alert_id descending like ZREVRANGEBYSCORE
+	sort.SliceStable(filteredAlerts, func(i, j int) bool {
+		timeI, _ := time.Parse(time.RFC3339, filteredAlerts[i].Timestamp)
+		timeJ, _ := time.Parse(time.RFC3339, filteredAlerts[j].Timestamp)
+		if !timeI.Equal(timeJ) {
+			return timeI.After(timeJ)
+		}
+		return filteredAlerts[i].AlertID > filteredAlerts[j].AlertID
+	})
+
+	// Apply limit
+	if limit > 0 && limit < len(filteredAlerts) {
+		filteredAlerts = filteredAlerts[:limit]
+	}
+	return filteredAlerts, nil
+}
+
+func (m *memoryAlertStore) Count(ctx context.Context, since, until *time.Time) (int, error) {
+	timeFilter := AlertFilter{Since: since, Until: until}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	count := 0
+	for _, alert := range m.alerts {
+		if timeFilter.Matches(alert) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (m *memoryAlertStore) Get(ctx context.Context, id string) (*models.Alert, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for i := range m.alerts {
+		if m.alerts[i].AlertID == id {
+			alert := m.alerts[i]
+			return &alert, nil
+		}
+	}
+	return nil, ErrAlertNotFound
+}
+
+func (m *memoryAlertStore) Update(ctx context.Context, id string, fn func(alert *models.Alert) error) (*models.Alert, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i := range m.alerts {
+		if m.alerts[i].AlertID != id {
+			continue
+		}
+		// Work on a copy so a failing fn leaves the stored alert untouched
+		alert := m.alerts[i]
+		alert.Comments = slices.Clone(alert.Comments)
+		alert.History = slices.Clone(alert.History)
+		if err := fn(&alert); err != nil {
+			return nil, err
+		}
+		m.alerts[i] = alert
+		return &alert, nil
+	}
+	return nil, ErrAlertNotFound
+}
+
+func (m *memoryAlertStore) Trim(ctx context.Context, before time.Time, maxCount int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	kept := make([]models.Alert, 0, len(m.alerts))
+	for _, alert := range m.alerts {
+		alertTime, err := time.Parse(time.RFC3339, alert.Timestamp)
+		if !before.IsZero() && err == nil && alertTime.Before(before) {
+			continue
+		}
+		kept = append(kept, alert)
+	}
+	if maxCount > 0 && len(kept) > maxCount {
+		// Keep the newest by timestamp (ties by alert_id) like the Redis sorted set,
+		// whatever order the alerts arrived in
+		sort.SliceStable(kept, func(i, j int) bool {
+			return compareAlertPositions(alertPosition(kept[i]), alertPosition(kept[j])) < 0
+		})
+		kept = kept[len(kept)-maxCount:]
+	}
+	m.alerts = kept
+	return nil
+}
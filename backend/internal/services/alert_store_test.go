@@ -0,0 +1,166 @@
+package services
+
+import (
+	"admin_server/backend/internal/models"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+var alertStoreBase = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// forEachAlertStore runs test against the Redis store (on miniredis) and the memory store
+func forEachAlertStore(t *testing.T, test func(t *testing.T, store alertStore)) {
+	t.Run("redis", func(t *testing.T) {
+		client, _ := newTestRedis(t)
+		test(t, newRedisAlertStore(client, "alerts"))
+	})
+	t.Run("memory", func(t *testing.T) {
+		test(t, newMemoryAlertStore())
+	})
+}
+
+// addTestAlert stores alert id at alertStoreBase plus offset seconds
+func addTestAlert(t *testing.T, store alertStore, id string, offset int) {
+	t.Helper()
+	ts := alertStoreBase.Add(time.Duration(offset) * time.Second)
+	alert := models.Alert{AlertID: id, Timestamp: ts.Format(time.RFC3339), RuleID: "R1", State: models.AlertStateOpen}
+	if err := store.Add(context.Background(), alert, ts); err != nil {
+		t.Fatalf("Add(%s): %v", id, err)
+	}
+}
+
+func alertIDs(alerts []models.Alert) []string {
+	ids := make([]string, len(alerts))
+	for i, alert := range alerts {
+		ids[i] = alert.AlertID
+	}
+	return ids
+}
+
+func TestAlertStoreAddIfAbsent(t *testing.T) {
+	forEachAlertStore(t, func(t *testing.T, store alertStore) {
+		ctx := context.Background()
+		addTestAlert(t, store, "a1", 0)
+		if _, err := store.Update(ctx, "a1", func(alert *models.Alert) error {
+			alert.Assignee = "alice"
+			return nil
+		}); err != nil {
+			t.Fatal(err)
+		}
+
+		redelivered := models.Alert{AlertID: "a1", Timestamp: alertStoreBase.Format(time.RFC3339), RuleID: "R1"}
+		if err := store.Add(ctx, redelivered, alertStoreBase); !errors.Is(err, ErrAlertExists) {
+			t.Fatalf("second Add err = %v, want ErrAlertExists", err)
+		}
+		stored, err := store.Get(ctx, "a1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if stored.Assignee != "alice" {
+			t.Fatalf("redelivery overwrote the stored alert: %+v", stored)
+		}
+		if count, _ := store.Count(ctx, nil, nil); count != 1 {
+			t.Fatalf("Count = %d, want 1", count)
+		}
+	})
+}
+
+func TestAlertStoreRangePagesAcrossTies(t *testing.T) {
+	forEachAlertStore(t, func(t *testing.T, store alertStore) {
+		ctx := context.Background()
+		// 같은 시각의 알림 여러 개: 커서는 (시각, alert_id)로 이어져야 함
+		for i, offset := range []int{0, 1, 1, 1, 2, 3} {
+			addTestAlert(t, store, fmt.Sprintf("a%d", i), offset)
+		}
+
+		var pages [][]string
+		var after *alertCursor
+		for {
+			page, err := store.Range(ctx, nil, nil, after, 2)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(page) == 0 {
+				break
+			}
+			pages = append(pages, alertIDs(page))
+			position := alertPosition(page[len(page)-1])
+			after = &position
+		}
+		want := "[[a5 a4] [a3 a2] [a1 a0]]"
+		if got := fmt.Sprint(pages); got != want {
+			t.Fatalf("pages = %s, want %s", got, want)
+		}
+
+		since, until := alertStoreBase, alertStoreBase.Add(2*time.Second)
+		window, err := store.Range(ctx, &since, &until, nil, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := fmt.Sprint(alertIDs(window)); got != "[a4 a3 a2 a1]" {
+			t.Fatalf("since < ts <= until = %s, want [a4 a3 a2 a1]", got)
+		}
+		if count, _ := store.Count(ctx, &since, &until); count != 4 {
+			t.Fatalf("Count = %d, want 4", count)
+		}
+	})
+}
+
+func TestAlertStoreTrim(t *testing.T) {
+	forEachAlertStore(t, func(t *testing.T, store alertStore) {
+		ctx := context.Background()
+		// 도착 순서와 시각 순서가 다름: 가장 최근 시각의 알림이 남아야 함
+		for _, alert := range []struct {
+			id     string
+			offset int
+		}{{"late-newest", 50}, {"old", 0}, {"middle", 20}, {"newer", 40}, {"late-oldish", 10}} {
+			addTestAlert(t, store, alert.id, alert.offset)
+		}
+
+		if err := store.Trim(ctx, alertStoreBase.Add(5*time.Second), 0); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := store.Get(ctx, "old"); !errors.Is(err, ErrAlertNotFound) {
+			t.Fatalf("alert older than before: err = %v, want ErrAlertNotFound", err)
+		}
+
+		if err := store.Trim(ctx, time.Time{}, 2); err != nil {
+			t.Fatal(err)
+		}
+		remaining, err := store.Range(ctx, nil, nil, nil, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := fmt.Sprint(alertIDs(remaining)); got != "[late-newest newer]" {
+			t.Fatalf("after max-count trim = %s, want the two newest by timestamp", got)
+		}
+		oldest, err := store.Oldest(ctx)
+		if err != nil || oldest == nil || !oldest.Equal(alertStoreBase.Add(40*time.Second)) {
+			t.Fatalf("Oldest = %v, %v, want the timestamp of newer", oldest, err)
+		}
+	})
+}
+
+func TestAlertStoreUpdateLeavesAlertOnError(t *testing.T) {
+	forEachAlertStore(t, func(t *testing.T, store alertStore) {
+		ctx := context.Background()
+		addTestAlert(t, store, "a1", 0)
+
+		failure := errors.New("rejected")
+		if _, err := store.Update(ctx, "a1", func(alert *models.Alert) error {
+			alert.Assignee = "mallory"
+			return failure
+		}); !errors.Is(err, failure) {
+			t.Fatalf("err = %v, want the fn error", err)
+		}
+		if stored, _ := store.Get(ctx, "a1"); stored.Assignee != "" {
+			t.Fatalf("failed update was written: %+v", stored)
+		}
+		if _, err := store.Update(ctx, "missing", func(*models.Alert) error { return nil }); !errors.Is(err, ErrAlertNotFound) {
+			t.Fatalf("missing alert: err = %v, want ErrAlertNotFound", err)
+		}
+	})
+}
@@ -0,0 +1,85 @@
+package services
+
+import (
+	"admin_server/backend/internal/config"
+	"admin_server/backend/internal/models"
+	"context"
+	"log"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// IncidentService groups repeated alerts into incidents
+type IncidentService struct {
+	cfg   *config.Config
+	store incidentStore
+}
+
+// NewIncidentService creates an IncidentService using the same backend as the alert store
+func NewIncidentService(cfg *config.Config, redisClient *redis.Client) *IncidentService {
+	var store incidentStore
+	if cfg.AlertStore == "memory" {
+		store = newMemoryIncidentStore()
+	} else {
+		store = newRedisIncidentStore(redisClient, cfg.IncidentRedisKey)
+	}
+
+	return &IncidentService{
+		cfg:   cfg,
+		store: store,
+	}
+}
+
+// Record assigns alert to an incident keyed by (rule_id, namespace, pod_name),
+// opening a new one when the previous alert is older than INCIDENT_WINDOW
+func (s *IncidentService) Record(alert models.Alert, ts time.Time) (*models.Incident, error) {
+	return s.store.Record(context.Background(), alert, ts, s.cfg.IncidentWindow)
+}
+
+// Trim drops incidents last seen before before. AlertService calls it with the
+// timestamp of the oldest alert it kept, so incidents go away with their alerts
+// whether those were trimmed by ALERT_RETENTION or ALERT_MAX_COUNT.
+func (s *IncidentService) Trim(before time.Time) error {
+	return s.store.Trim(context.Background(), before)
+}
+
+// GetIncident returns a single incident by ID
+func (s *IncidentService) GetIncident(id string) (*models.Incident, error) {
+	return s.store.Get(context.Background(), id)
+}
+
+// GetIncidents lists incidents (most recently seen first) last seen after since
+func (s *IncidentService) GetIncidents(limit int, since *time.Time, ruleID, namespace, podName string) (*models.IncidentsResponse, error) {
+	log.Println("Getting incidents from incident store")
+
+	incidents, err := s.store.List(context.Background(), since)
+	if err != nil {
+		log.Printf("ERROR: Failed to retrieve incidents: %v", err)
+		return nil, err
+	}
+
+	matched := make([]models.Incident, 0)
+	for _, incident := range incidents {
+		if ruleID != "" && incident.RuleID != ruleID {
+			continue
+		}
+		if namespace != "" && incident.Namespace != namespace {
+			continue
+		}
+		if podName != "" && incident.PodName != podName {
+			continue
+		}
+		matched = append(matched, incident)
+	}
+
+	page := matched
+	if limit > 0 && limit < len(page) {
+		page = page[:limit]
+	}
+
+	return &models.IncidentsResponse{
+		Incidents: page,
+		Total:     len(matched),
+	}, nil
+}
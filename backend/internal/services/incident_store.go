@@ -0,0 +1,362 @@
+package services
+
+import (
+	"admin_server/backend/internal/models"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// ErrIncidentNotFound is returned when no incident has the requested ID
+var ErrIncidentNotFound = errors.New("incident not found")
+
+// incidentStore groups alerts into incidents
+type incidentStore interface {
+	// Record merges alert into the active incident for its key when the incident was
+	// last seen within window, otherwise opens a new incident
+	Record(ctx context.Context, alert models.Alert, ts time.Time, window time.Duration) (*models.Incident, error)
+	Get(ctx context.Context, id string) (*models.Incident, error)
+	// List returns incidents last seen after since (all when nil), most recent first
+	List(ctx context.Context, since *time.Time) ([]models.Incident, error)
+	// Trim drops incidents last seen before before
+	Trim(ctx context.Context, before time.Time) error
+}
+
+// incidentKey is the grouping key of an incident: rule_id, namespace and pod_name
+func incidentKey(ruleID, namespace, podName string) string {
+	return ruleID + "|" + namespace + "|" + podName
+}
+
+func newIncident(alert models.Alert, ts time.Time) *models.Incident {
+	seen := ts.UTC().Format(time.RFC3339)
+	return &models.Incident{
+		IncidentID:      uuid.NewString(),
+		RuleID:          alert.RuleID,
+		RuleDescription: alert.RuleDescription,
+		Namespace:       alert.Namespace,
+		PodName:         alert.PodName,
+		Severity:        alert.Severity,
+		FirstSeen:       seen,
+		LastSeen:        seen,
+		Count:           1,
+	}
+}
+
+// mergeIncident adds alert to incident if it falls within window of the incident's
+// last_seen and reports whether it did
+func mergeIncident(incident *models.Incident, alert models.Alert, ts time.Time, window time.Duration) bool {
+	lastSeen, err := time.Parse(time.RFC3339, incident.LastSeen)
+	if err != nil || ts.Sub(lastSeen) > window {
+		return false
+	}
+
+	incident.Count++
+	if ts.After(lastSeen) {
+		incident.LastSeen = ts.UTC().Format(time.RFC3339)
+	}
+	if firstSeen, err := time.Parse(time.RFC3339, incident.FirstSeen); err == nil && ts.Before(firstSeen) {
+		incident.FirstSeen = ts.UTC().Format(time.RFC3339)
+	}
+	if models.SeverityRank(alert.Severity) > models.SeverityRank(incident.Severity) {
+		incident.Severity = alert.Severity
+	}
+	return true
+}
+
+// redisIncidentStore keeps each incident as JSON under its own key, incident IDs in a
+// sorted set scored by last_seen (unix ms), and the active incident ID of each grouping
+// key under its own key, so Record only watches the grouping key it records into.
+type redisIncidentStore struct {
+	client       *redis.Client
+	indexKey     string
+	keyPrefix    string
+	activePrefix string
+}
+
+func newRedisIncidentStore(client *redis.Client, prefix string) *redisIncidentStore {
+	return &redisIncidentStore{
+		client:       client,
+		indexKey:     prefix + ":index",
+		keyPrefix:    prefix + ":incident:",
+		activePrefix: prefix + ":active:",
+	}
+}
+
+func (r *redisIncidentStore) dataKey(id string) string {
+	return r.keyPrefix + id
+}
+
+func (r *redisIncidentStore) activeKey(groupKey string) string {
+	return r.activePrefix + groupKey
+}
+
+func (r *redisIncidentStore) Record(ctx context.Context, alert models.Alert, ts time.Time, window time.Duration) (*models.Incident, error) {
+	key := incidentKey(alert.RuleID, alert.Namespace, alert.PodName)
+	activeKey := r.activeKey(key)
+	var recorded *models.Incident
+
+	// WATCH only this grouping key's active pointer: every write to an incident goes
+	// through it, so alerts for other rules or pods never abort this transaction
+	txf := func(tx *redis.Tx) error {
+		var incident *models.Incident
+
+		id, err := tx.Get(ctx, activeKey).Result()
+		if err != nil && !errors.Is(err, redis.Nil) {
+			return fmt.Errorf("failed to read active incident: %w", err)
+		}
+		if id != "" {
+			current, err := r.get(ctx, tx, id)
+			if err != nil && !errors.Is(err, ErrIncidentNotFound) {
+				return err
+			}
+			if current != nil && mergeIncident(current, alert, ts, window) {
+				incident = current
+			}
+		}
+		if incident == nil {
+			incident = newIncident(alert, ts)
+		}
+
+		incidentJSON, err := json.Marshal(incident)
+		if err != nil {
+			return fmt.Errorf("failed to marshal incident: %w", err)
+		}
+		lastSeen, _ := time.Parse(time.RFC3339, incident.LastSeen)
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Set(ctx, r.dataKey(incident.IncidentID), incidentJSON, 0)
+			pipe.Set(ctx, activeKey, incident.IncidentID, 0)
+			pipe.ZAdd(ctx, r.indexKey, redis.Z{Score: float64(lastSeen.UnixMilli()), Member: incident.IncidentID})
+			return nil
+		})
+		recorded = incident
+		return err
+	}
+
+	for i := 0; i < maxUpdateRetries; i++ {
+		err := r.client.Watch(ctx, txf, activeKey)
+		if errors.Is(err, redis.TxFailedErr) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return recorded, nil
+	}
+	return nil, fmt.Errorf("failed to record incident for %s: too many concurrent modifications", key)
+}
+
+func (r *redisIncidentStore) Get(ctx context.Context, id string) (*models.Incident, error) {
+	return r.get(ctx, r.client, id)
+}
+
+func (r *redisIncidentStore) get(ctx context.Context, client redis.Cmdable, id string) (*models.Incident, error) {
+	raw, err := client.Get(ctx, r.dataKey(id)).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrIncidentNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read incident from Redis: %w", err)
+	}
+	var incident models.Incident
+	if err := json.Unmarshal([]byte(raw), &incident); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal incident %s: %w", id, err)
+	}
+	return &incident, nil
+}
+
+// load reads the incidents with the given IDs in order, skipping ones that are gone
+func (r *redisIncidentStore) load(ctx context.Context, ids []string) ([]models.Incident, error) {
+	incidents := make([]models.Incident, 0, len(ids))
+	if len(ids) == 0 {
+		return incidents, nil
+	}
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = r.dataKey(id)
+	}
+	values, err := r.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read incidents from Redis: %w", err)
+	}
+	for i, value := range values {
+		raw, ok := value.(string)
+		if !ok {
+			continue
+		}
+		var incident models.Incident
+		if err := json.Unmarshal([]byte(raw), &incident); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal incident %s: %w", ids[i], err)
+		}
+		incidents = append(incidents, incident)
+	}
+	return incidents, nil
+}
+
+func (r *redisIncidentStore) List(ctx context.Context, since *time.Time) ([]models.Incident, error) {
+	opt := &redis.ZRangeBy{Min: "-inf", Max: "+inf"}
+	if since != nil {
+		opt.Min = "(" + strconv.FormatInt(since.UnixMilli(), 10)
+	}
+	ids, err := r.client.ZRevRangeByScore(ctx, r.indexKey, opt).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read incident index from Redis: %w", err)
+	}
+	return r.load(ctx, ids)
+}
+
+// deleteActiveScript deletes each active pointer that still holds the given incident ID,
+// leaving pointers already moved to a newer incident alone.
+// KEYS: active keys; ARGV: the expired incident ID for each key
+var deleteActiveScript = redis.NewScript(`
+for i, key in ipairs(KEYS) do
+	if redis.call('GET', key) == ARGV[i] then
+		redis.call('DEL', key)
+	end
+end
+return 0
+`)
+
+func (r *redisIncidentStore) Trim(ctx context.Context, before time.Time) error {
+	if before.IsZero() {
+		return nil
+	}
+	expired, err := r.client.ZRangeByScore(ctx, r.indexKey, &redis.ZRangeBy{
+		Min: "-inf",
+		Max: "(" + strconv.FormatInt(before.UnixMilli(), 10),
+	}).Result()
+	if err != nil {
+		return fmt.Errorf("failed to read expired incidents: %w", err)
+	}
+	if len(expired) == 0 {
+		return nil
+	}
+
+	// Drop active pointers that still reference an expired incident
+	incidents, err := r.load(ctx, expired)
+	if err != nil {
+		return err
+	}
+	activeKeys := make([]string, len(incidents))
+	activeIDs := make([]interface{}, len(incidents))
+	for i, incident := range incidents {
+		activeKeys[i] = r.activeKey(incidentKey(incident.RuleID, incident.Namespace, incident.PodName))
+		activeIDs[i] = incident.IncidentID
+	}
+
+	members := make([]interface{}, len(expired))
+	keys := make([]string, len(expired))
+	for i, id := range expired {
+		members[i] = id
+		keys[i] = r.dataKey(id)
+	}
+	_, err = r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		if len(activeKeys) > 0 {
+			// 파이프라인에서는 NOSCRIPT 재시도가 안 되므로 EVALSHA 대신 EVAL
+			deleteActiveScript.Eval(ctx, pipe, activeKeys, activeIDs...)
+		}
+		pipe.ZRem(ctx, r.indexKey, members...)
+		pipe.Del(ctx, keys...)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to trim incidents: %w", err)
+	}
+	return nil
+}
+
+// memoryIncidentStore keeps incidents in process memory (local development only)
+type memoryIncidentStore struct {
+	mu        sync.RWMutex
+	incidents map[string]*models.Incident
+	active    map[string]string // incident key -> incident ID
+}
+
+func newMemoryIncidentStore() *memoryIncidentStore {
+	return &memoryIncidentStore{
+		incidents: make(map[string]*models.Incident),
+		active:    make(map[string]string),
+	}
+}
+
+func (m *memoryIncidentStore) Record(ctx context.Context, alert models.Alert, ts time.Time, window time.Duration) (*models.Incident, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := incidentKey(alert.RuleID, alert.Namespace, alert.PodName)
+	if current, ok := m.incidents[m.active[key]]; ok && mergeIncident(current, alert, ts, window) {
+		incident := *current
+		return &incident, nil
+	}
+
+	incident := newIncident(alert, ts)
+	m.incidents[incident.IncidentID] = incident
+	m.active[key] = incident.IncidentID
+	recorded := *incident
+	return &recorded, nil
+}
+
+func (m *memoryIncidentStore) Get(ctx context.Context, id string) (*models.Incident, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	incident, ok := m.incidents[id]
+	if !ok {
+		return nil, ErrIncidentNotFound
+	}
+	found := *incident
+	return &found, nil
+}
+
+func (m *memoryIncidentStore) List(ctx context.Context, since *time.Time) ([]models.Incident, error) {
+	m.mu.RLock()
+	incidents := make([]models.Incident, 0, len(m.incidents))
+	for _, incident := range m.incidents {
+		if since != nil {
+			lastSeen, err := time.Parse(time.RFC3339, incident.LastSeen)
+			if err != nil || !lastSeen.After(*since) {
+				continue
+			}
+		}
+		incidents = append(incidents, *incident)
+	}
+	m.mu.RUnlock()
+
+	sort.Slice(incidents, func(i, j int) bool {
+		if incidents[i].LastSeen != incidents[j].LastSeen {
+			return incidents[i].LastSeen > incidents[j].LastSeen
+		}
+		return incidents[i].IncidentID > incidents[j].IncidentID
+	})
+	return incidents, nil
+}
+
+func (m *memoryIncidentStore) Trim(ctx context.Context, before time.Time) error {
+	if before.IsZero() {
+		return nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for id, incident := range m.incidents {
+		lastSeen, err := time.Parse(time.RFC3339, incident.LastSeen)
+		if err == nil && lastSeen.Before(before) {
+			delete(m.incidents, id)
+		}
+	}
+	for key, id := range m.active {
+		if _, ok := m.incidents[id]; !ok {
+			delete(m.active, key)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,170 @@
+package services
+
+import (
+	"admin_server/backend/internal/models"
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+// newTestRedis returns a client for a fresh miniredis server
+func newTestRedis(t *testing.T) (*redis.Client, *miniredis.Miniredis) {
+	t.Helper()
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+	return client, server
+}
+
+func TestRedisIncidentStoreGroupsWithinWindow(t *testing.T) {
+	client, _ := newTestRedis(t)
+	store := newRedisIncidentStore(client, "incidents")
+	ctx := context.Background()
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	alert := models.Alert{RuleID: "R1", Namespace: "default", PodName: "web", Severity: "medium"}
+
+	first, err := store.Record(ctx, alert, base, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	alert.Severity = "critical"
+	merged, err := store.Record(ctx, alert, base.Add(30*time.Second), time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if merged.IncidentID != first.IncidentID || merged.Count != 2 || merged.Severity != "critical" {
+		t.Fatalf("merged = %+v, want incident %s with count 2 and severity critical", merged, first.IncidentID)
+	}
+	later, err := store.Record(ctx, alert, base.Add(5*time.Minute), time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if later.IncidentID == first.IncidentID {
+		t.Fatal("an alert after the window joined the old incident")
+	}
+
+	incidents, err := store.List(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(incidents) != 2 || incidents[0].IncidentID != later.IncidentID {
+		t.Fatalf("List = %+v, want the new incident first", incidents)
+	}
+}
+
+// Alerts for different rules and pods watch different keys, so a burst of them must
+// not exhaust each other's retry budget
+func TestRedisIncidentStoreConcurrentUnrelatedAlerts(t *testing.T) {
+	client, _ := newTestRedis(t)
+	store := newRedisIncidentStore(client, "incidents")
+	ctx := context.Background()
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	const pods, perPod = 20, 10
+
+	var wg sync.WaitGroup
+	for p := 0; p < pods; p++ {
+		wg.Add(1)
+		go func(p int) {
+			defer wg.Done()
+			alert := models.Alert{RuleID: "R1", Namespace: "default", PodName: fmt.Sprintf("pod-%d", p), Severity: "high"}
+			for i := 0; i < perPod; i++ {
+				if _, err := store.Record(ctx, alert, base.Add(time.Duration(i)*time.Second), time.Minute); err != nil {
+					t.Errorf("Record(pod-%d): %v", p, err)
+				}
+			}
+		}(p)
+	}
+	wg.Wait()
+
+	incidents, err := store.List(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(incidents) != pods {
+		t.Fatalf("%d incidents, want one per pod (%d)", len(incidents), pods)
+	}
+	for _, incident := range incidents {
+		if incident.Count != perPod {
+			t.Fatalf("incident for %s counted %d alerts, want %d", incident.PodName, incident.Count, perPod)
+		}
+	}
+}
+
+func TestRedisIncidentStoreTrim(t *testing.T) {
+	client, server := newTestRedis(t)
+	store := newRedisIncidentStore(client, "incidents")
+	ctx := context.Background()
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	web := models.Alert{RuleID: "R1", Namespace: "default", PodName: "web", Severity: "high"}
+	db := models.Alert{RuleID: "R1", Namespace: "default", PodName: "db", Severity: "high"}
+
+	old, err := store.Record(ctx, web, base, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// web의 활성 포인터는 새 인시던트로 옮겨졌으므로 오래된 인시던트를 지워도 유지되어야 함
+	current, err := store.Record(ctx, web, base.Add(time.Hour), time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Record(ctx, db, base, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := store.Trim(ctx, base.Add(time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Get(ctx, old.IncidentID); err != ErrIncidentNotFound {
+		t.Fatalf("Get(trimmed) err = %v, want ErrIncidentNotFound", err)
+	}
+	if server.Exists("incidents:active:R1|default|db") {
+		t.Fatal("active pointer of a trimmed incident was kept")
+	}
+	if id, _ := server.Get("incidents:active:R1|default|web"); id != current.IncidentID {
+		t.Fatalf("active pointer for web = %q, want the newer incident %s", id, current.IncidentID)
+	}
+	incidents, err := store.List(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(incidents) != 1 || incidents[0].IncidentID != current.IncidentID {
+		t.Fatalf("List = %+v, want only the newer incident", incidents)
+	}
+}
+
+func TestIncidentsAreTrimmedWithTheirAlerts(t *testing.T) {
+	service := newTestAlertService(t)
+	service.cfg.AlertRetention = 0
+	service.cfg.AlertMaxCount = 2
+	base := time.Now().UTC().Add(-time.Hour).Truncate(time.Second)
+
+	for i, pod := range []string{"a", "b", "c"} {
+		if err := service.ReceiveWebhook(&models.WebhookAlert{
+			AlertID:   "alert-" + pod,
+			Timestamp: base.Add(time.Duration(i) * time.Minute).Format(time.RFC3339),
+			RuleID:    "R1",
+			Severity:  "high",
+			PodName:   pod,
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// ALERT_MAX_COUNT가 pod a의 알림을 지웠으므로 그 인시던트도 사라져야 함
+	incidents, err := service.incidents.GetIncidents(0, nil, "", "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pods := map[string]bool{}
+	for _, incident := range incidents.Incidents {
+		pods[incident.PodName] = true
+	}
+	if len(pods) != 2 || pods["a"] {
+		t.Fatalf("incidents for pods %v, want only b and c", pods)
+	}
+}
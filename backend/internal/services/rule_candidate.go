@@ -0,0 +1,190 @@
+package services
+
+import (
+	"admin_server/backend/internal/models"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"gopkg.in/yaml.v3"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Annotations recorded on the candidate ConfigMap
+const (
+	annotationStagedBy = "admin-server/staged-by"
+	annotationStagedAt = "admin-server/staged-at"
+)
+
+// ErrCandidateNotFound is returned when no candidate RuleSet has been staged
+var ErrCandidateNotFound = errors.New("no candidate ruleset is staged")
+
+// GetCandidateRules returns the staged candidate RuleSet and its ConfigMap resourceVersion
+func (s *RuleService) GetCandidateRules() (*models.RuleSet, string, error) {
+	configMap, ruleSet, err := s.getRuleConfigMap(s.ruleset.CandidateConfigMap)
+	if apierrors.IsNotFound(err) {
+		return nil, "", ErrCandidateNotFound
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	return ruleSet, configMap.ResourceVersion, nil
+}
+
+// StageRules writes ruleSet to the candidate ConfigMap, creating it on first use, so the
+// rule engine can run it in shadow mode. expectedVersion is the candidate's resourceVersion
+// (If-Match); empty or "*" overwrites whatever is staged. Template rules are staged expanded.
+// A candidate ConfigMap whose ruleset key is missing or unparsable is overwritten.
+func (s *RuleService) StageRules(ruleSet *models.RuleSet, expectedVersion, author string) (*models.UpdateRulesResponse, error) {
+	ruleSet, err := s.expandRuleSet(ruleSet)
+	if err != nil {
+		return nil, err
+	}
+	yamlData, err := yaml.Marshal(ruleSet)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal rules to YAML: %w", err)
+	}
+	if author == "" {
+		author = "anonymous"
+	}
+	annotations := map[string]string{
+		annotationStagedBy: author,
+		annotationStagedAt: time.Now().UTC().Format(time.RFC3339),
+	}
+	configMaps := s.clientset.CoreV1().ConfigMaps(s.ruleset.Namespace)
+	name := s.ruleset.CandidateConfigMap
+
+	log.Printf("Staging ruleset_version %s to candidate ConfigMap '%s'", ruleSet.RulesetVersion, name)
+
+	configMap, current, err := s.getStagedConfigMap()
+	var updated *corev1.ConfigMap
+	switch {
+	case apierrors.IsNotFound(err):
+		updated, err = configMaps.Create(context.TODO(), &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Namespace:   s.ruleset.Namespace,
+				Annotations: annotations,
+			},
+			Data: map[string]string{s.ruleset.Key: string(yamlData)},
+		}, metav1.CreateOptions{FieldManager: ruleFieldManager})
+		if apierrors.IsAlreadyExists(err) {
+			return nil, s.candidateConflict(ruleSet)
+		}
+	case err != nil:
+		return nil, err
+	default:
+		if expectedVersion != "" && expectedVersion != AnyResourceVersion && configMap.ResourceVersion != expectedVersion {
+			return nil, s.conflict(configMap.ResourceVersion, current, ruleSet)
+		}
+		if configMap.Annotations == nil {
+			configMap.Annotations = map[string]string{}
+		}
+		for k, v := range annotations {
+			configMap.Annotations[k] = v
+		}
+		if configMap.Data == nil {
+			configMap.Data = map[string]string{}
+		}
+		configMap.Data[s.ruleset.Key] = string(yamlData)
+		updated, err = configMaps.Update(context.TODO(), configMap, metav1.UpdateOptions{FieldManager: ruleFieldManager})
+		if apierrors.IsConflict(err) {
+			return nil, s.candidateConflict(ruleSet)
+		}
+	}
+	if err != nil {
+		log.Printf("ERROR: Failed to write candidate ConfigMap via K8s API: %v", err)
+		return nil, fmt.Errorf("failed to write candidate ConfigMap via K8s API: %w", err)
+	}
+
+	return &models.UpdateRulesResponse{
+		Status:          "success",
+		Message:         "Candidate ConfigMap updated; promote it with POST " + s.rulesPath() + "/promote.",
+		NewVersion:      ruleSet.RulesetVersion,
+		ResourceVersion: updated.ResourceVersion,
+	}, nil
+}
+
+// PromoteCandidate copies the candidate RuleSet into the live ConfigMap through the same
+// validation and update path as PUT /rules. expectedVersion refers to the live ConfigMap.
+func (s *RuleService) PromoteCandidate(expectedVersion, author string) (*models.UpdateRulesResponse, error) {
+	candidate, _, err := s.GetCandidateRules()
+	if err != nil {
+		return nil, err
+	}
+	warnings, err := s.ValidateRules(candidate, false)
+	if err != nil {
+		return nil, fmt.Errorf("%w: candidate: %w", ErrInvalidRuleSet, err)
+	}
+
+	log.Printf("Promoting candidate ruleset_version %s to live", candidate.RulesetVersion)
+	response, err := s.UpdateRules(candidate, expectedVersion, author)
+	if err != nil {
+		return nil, err
+	}
+	response.Message = fmt.Sprintf("Candidate ruleset_version %s promoted to live.", candidate.RulesetVersion)
+	response.Warnings = warnings
+	return response, nil
+}
+
+// GetRuleStatus reports which ruleset_version is live and which is staged as the candidate
+func (s *RuleService) GetRuleStatus() (*models.RuleStatusResponse, error) {
+	liveMap, live, err := s.getRuleConfigMap(s.ruleset.ConfigMap)
+	if err != nil {
+		return nil, err
+	}
+	status := &models.RuleStatusResponse{Live: ruleDeployment(liveMap, live)}
+
+	candidateMap, candidate, err := s.getRuleConfigMap(s.ruleset.CandidateConfigMap)
+	if apierrors.IsNotFound(err) {
+		return status, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	status.Candidate = ruleDeployment(candidateMap, candidate)
+	status.Candidate.StagedBy = candidateMap.Annotations[annotationStagedBy]
+	status.Candidate.StagedAt = candidateMap.Annotations[annotationStagedAt]
+	diff := DiffRuleSets(live, candidate)
+	status.Diff = &diff
+	return status, nil
+}
+
+func ruleDeployment(configMap *corev1.ConfigMap, ruleSet *models.RuleSet) *models.RuleDeployment {
+	return &models.RuleDeployment{
+		ConfigMap:       configMap.Name,
+		RulesetVersion:  ruleSet.RulesetVersion,
+		ResourceVersion: configMap.ResourceVersion,
+		RuleCount:       len(ruleSet.Rules),
+	}
+}
+
+// candidateConflict re-reads the candidate after a lost write race
+func (s *RuleService) candidateConflict(proposed *models.RuleSet) error {
+	configMap, current, err := s.getStagedConfigMap()
+	if err != nil {
+		return fmt.Errorf("candidate ConfigMap was modified concurrently: %w", err)
+	}
+	return s.conflict(configMap.ResourceVersion, current, proposed)
+}
+
+// getStagedConfigMap reads the candidate ConfigMap for staging. A missing or unparsable
+// ruleset key counts as an empty RuleSet so a broken candidate can be restaged over.
+func (s *RuleService) getStagedConfigMap() (*corev1.ConfigMap, *models.RuleSet, error) {
+	name := s.ruleset.CandidateConfigMap
+	configMap, err := s.clientset.CoreV1().ConfigMaps(s.ruleset.Namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get ConfigMap via K8s API: %w", err)
+	}
+	ruleSet, err := parseRuleConfigMap(configMap, s.ruleset.Key)
+	if err != nil {
+		// 깨진 후보는 비어 있는 것으로 보고 덮어씀
+		log.Printf("WARNING: candidate ConfigMap %s is unreadable and will be overwritten: %v", name, err)
+		ruleSet = &models.RuleSet{}
+	}
+	return configMap, ruleSet, nil
+}
@@ -0,0 +1,54 @@
+package services
+
+import (
+	"admin_server/backend/internal/models"
+	"encoding/json"
+)
+
+// DiffRuleSets compares two RuleSets by rule_id. Rules are compared by their
+// JSON form so YAML-decoded and JSON-decoded values (int vs float64) compare equal.
+func DiffRuleSets(from, to *models.RuleSet) models.RuleSetDiff {
+	diff := models.RuleSetDiff{
+		RulesetVersionFrom: from.RulesetVersion,
+		RulesetVersionTo:   to.RulesetVersion,
+		DescriptionChanged: from.Description != to.Description,
+		Added:              make([]models.Rule, 0),
+		Removed:            make([]models.Rule, 0),
+		Modified:           make([]models.RuleChange, 0),
+	}
+
+	fromRules := make(map[string]models.Rule, len(from.Rules))
+	for _, rule := range from.Rules {
+		fromRules[rule.RuleID] = rule
+	}
+	toRules := make(map[string]struct{}, len(to.Rules))
+
+	for _, rule := range to.Rules {
+		toRules[rule.RuleID] = struct{}{}
+		current, ok := fromRules[rule.RuleID]
+		if !ok {
+			diff.Added = append(diff.Added, rule)
+			continue
+		}
+		if !sameRule(current, rule) {
+			diff.Modified = append(diff.Modified, models.RuleChange{
+				RuleID:   rule.RuleID,
+				Current:  current,
+				Proposed: rule,
+			})
+		}
+	}
+	for _, rule := range from.Rules {
+		if _, ok := toRules[rule.RuleID]; !ok {
+			diff.Removed = append(diff.Removed, rule)
+		}
+	}
+
+	return diff
+}
+
+func sameRule(a, b models.Rule) bool {
+	rawA, errA := json.Marshal(a)
+	rawB, errB := json.Marshal(b)
+	return errA == nil && errB == nil && string(rawA) == string(rawB)
+}
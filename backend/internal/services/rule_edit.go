@@ -0,0 +1,278 @@
+package services
+
+import (
+	"admin_server/backend/internal/models"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	jsonpatch "gopkg.in/evanphx/json-patch.v4"
+)
+
+// Semver parts RULE_VERSION_BUMP can name
+const (
+	VersionBumpMajor = "major"
+	VersionBumpMinor = "minor"
+	VersionBumpPatch = "patch"
+	VersionBumpNone  = "none"
+)
+
+// Patch document types accepted by PatchRules (the request Content-Type)
+const (
+	JSONPatchType  = "application/json-patch+json"  // RFC 6902
+	MergePatchType = "application/merge-patch+json" // RFC 7386
+)
+
+var (
+	// ErrRuleNotFound is returned when no rule has the requested rule_id
+	ErrRuleNotFound = errors.New("rule not found")
+	// ErrRuleExists is returned when creating a rule whose rule_id is taken
+	ErrRuleExists = errors.New("rule already exists")
+	// ErrInvalidPatch is returned for patch documents that cannot be decoded or applied
+	ErrInvalidPatch = errors.New("invalid patch")
+	// ErrUnsupportedPatchType is returned for a Content-Type other than JSONPatchType or MergePatchType
+	ErrUnsupportedPatchType = errors.New("unsupported patch type (use " + JSONPatchType + " or " + MergePatchType + ")")
+)
+
+// GetRule returns a single rule from the live RuleSet along with the ConfigMap
+// resourceVersion, which is the ETag for edits of any rule
+func (s *RuleService) GetRule(ruleID string) (*models.Rule, string, error) {
+	ruleSet, resourceVersion, err := s.GetRules()
+	if err != nil {
+		return nil, "", err
+	}
+	i := findRule(ruleSet, ruleID)
+	if i < 0 {
+		return nil, "", fmt.Errorf("%w: %s", ErrRuleNotFound, ruleID)
+	}
+	return &ruleSet.Rules[i], resourceVersion, nil
+}
+
+// CreateRule appends rule to the live RuleSet. rule.RuleID defaults to ruleID and
+// must match it when set.
+func (s *RuleService) CreateRule(ruleID string, rule models.Rule, expectedVersion, author string, strict bool) (*models.UpdateRulesResponse, error) {
+	if err := checkRuleID(ruleID, &rule); err != nil {
+		return nil, err
+	}
+	return s.editRules(expectedVersion, author, strict, func(ruleSet *models.RuleSet) error {
+		if findRule(ruleSet, ruleID) >= 0 {
+			return fmt.Errorf("%w: %s", ErrRuleExists, ruleID)
+		}
+		ruleSet.Rules = append(ruleSet.Rules, rule)
+		return nil
+	})
+}
+
+// ReplaceRule replaces the rule with ruleID, keeping its position in the RuleSet
+func (s *RuleService) ReplaceRule(ruleID string, rule models.Rule, expectedVersion, author string, strict bool) (*models.UpdateRulesResponse, error) {
+	if err := checkRuleID(ruleID, &rule); err != nil {
+		return nil, err
+	}
+	return s.editRules(expectedVersion, author, strict, func(ruleSet *models.RuleSet) error {
+		i := findRule(ruleSet, ruleID)
+		if i < 0 {
+			return fmt.Errorf("%w: %s", ErrRuleNotFound, ruleID)
+		}
+		ruleSet.Rules[i] = rule
+		return nil
+	})
+}
+
+// DeleteRule removes the rule with ruleID
+func (s *RuleService) DeleteRule(ruleID, expectedVersion, author string) (*models.UpdateRulesResponse, error) {
+	return s.editRules(expectedVersion, author, false, func(ruleSet *models.RuleSet) error {
+		i := findRule(ruleSet, ruleID)
+		if i < 0 {
+			return fmt.Errorf("%w: %s", ErrRuleNotFound, ruleID)
+		}
+		ruleSet.Rules = append(ruleSet.Rules[:i], ruleSet.Rules[i+1:]...)
+		return nil
+	})
+}
+
+// PatchRules applies a JSON Patch or JSON merge patch to the JSON form of the live
+// RuleSet. Note that a merge patch replaces arrays wholesale, so editing a single
+// rule is done with a JSON Patch (e.g. {"op":"replace","path":"/rules/0/description"}).
+func (s *RuleService) PatchRules(patchType string, patch []byte, expectedVersion, author string, strict bool) (*models.UpdateRulesResponse, error) {
+	var apply func(doc []byte) ([]byte, error)
+	switch patchType {
+	case JSONPatchType:
+		decoded, err := jsonpatch.DecodePatch(patch)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidPatch, err)
+		}
+		apply = decoded.Apply
+	case MergePatchType:
+		if !json.Valid(patch) {
+			return nil, fmt.Errorf("%w: body is not valid JSON", ErrInvalidPatch)
+		}
+		apply = func(doc []byte) ([]byte, error) { return jsonpatch.MergePatch(doc, patch) }
+	default:
+		return nil, ErrUnsupportedPatchType
+	}
+
+	return s.editRules(expectedVersion, author, strict, func(ruleSet *models.RuleSet) error {
+		doc, err := json.Marshal(ruleSet)
+		if err != nil {
+			return fmt.Errorf("failed to marshal rules to JSON: %w", err)
+		}
+		patched, err := apply(doc)
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidPatch, err)
+		}
+
+		var result models.RuleSet
+		decoder := json.NewDecoder(bytes.NewReader(patched))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&result); err != nil {
+			return fmt.Errorf("%w: patched ruleset: %w", ErrInvalidPatch, err)
+		}
+		*ruleSet = result
+		return nil
+	})
+}
+
+// editRules applies edit to a fresh copy of the live RuleSet and writes it back through
+// UpdateRules. Without If-Match (expectedVersion empty or "*") a write that loses a race
+// is re-applied on the new RuleSet, up to RULE_EDIT_RETRIES attempts; with If-Match the
+// conflict goes straight back to the caller. An edit that leaves ruleset_version alone
+// gets it bumped according to RULE_VERSION_BUMP. Template rules are re-expanded on every
+// edit, so a changed template reaches the rules built from it with their next write.
+func (s *RuleService) editRules(expectedVersion, author string, strict bool, edit func(*models.RuleSet) error) (*models.UpdateRulesResponse, error) {
+	pinned := expectedVersion != "" && expectedVersion != AnyResourceVersion
+	attempts := s.cfg.RuleEditRetries
+	if attempts < 1 || pinned {
+		attempts = 1
+	}
+
+	var lastErr error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		configMap, current, err := s.getRuleConfigMap(s.ruleset.ConfigMap)
+		if err != nil {
+			return nil, err
+		}
+
+		proposed := cloneRuleSet(current)
+		if err := edit(proposed); err != nil {
+			return nil, err
+		}
+		// 전개할 수 없는 룰은 그대로 두고, 아래 ValidateRules가 문제로 보고한다
+		if _, err := s.applyTemplates(proposed); err != nil {
+			return nil, err
+		}
+		if sameRuleSet(proposed, current) {
+			return &models.UpdateRulesResponse{
+				Status:          "success",
+				Message:         "No changes; rule.yaml ConfigMap left as is.",
+				NewVersion:      current.RulesetVersion,
+				ResourceVersion: configMap.ResourceVersion,
+			}, nil
+		}
+
+		var bumpWarning []models.ValidationProblem
+		if proposed.RulesetVersion == current.RulesetVersion {
+			bumped, err := bumpVersion(current.RulesetVersion, s.cfg.RuleVersionBump)
+			if err != nil {
+				bumpWarning = append(bumpWarning, models.ValidationProblem{Pointer: "/ruleset_version", Message: err.Error() + "; left unchanged"})
+			} else {
+				proposed.RulesetVersion = bumped
+			}
+		}
+
+		warnings, err := s.ValidateRules(proposed, strict)
+		if err != nil {
+			return nil, err
+		}
+
+		version := configMap.ResourceVersion
+		if pinned {
+			version = expectedVersion
+		}
+		response, err := s.UpdateRules(proposed, version, author)
+		var conflict *RuleConflictError
+		if errors.As(err, &conflict) {
+			log.Printf("Rule edit lost a write race (attempt %d/%d, resourceVersion %s)", attempt, attempts, conflict.ResourceVersion)
+			lastErr = err
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		response.Warnings = append(warnings, bumpWarning...)
+		return response, nil
+	}
+	return nil, lastErr
+}
+
+// bumpVersion increments the named part of a MAJOR.MINOR.PATCH version, keeping a
+// leading "v" and dropping any pre-release or build suffix
+func bumpVersion(version, part string) (string, error) {
+	if part == VersionBumpNone {
+		return version, nil
+	}
+
+	prefix := ""
+	core := version
+	if strings.HasPrefix(core, "v") {
+		prefix, core = "v", core[1:]
+	}
+	if i := strings.IndexAny(core, "-+"); i >= 0 {
+		core = core[:i]
+	}
+	fields := strings.Split(core, ".")
+	if len(fields) != 3 {
+		return "", fmt.Errorf("ruleset_version %q is not a semantic version", version)
+	}
+	var nums [3]int
+	for i, field := range fields {
+		n, err := strconv.Atoi(field)
+		if err != nil || n < 0 {
+			return "", fmt.Errorf("ruleset_version %q is not a semantic version", version)
+		}
+		nums[i] = n
+	}
+
+	switch part {
+	case VersionBumpMajor:
+		nums = [3]int{nums[0] + 1, 0, 0}
+	case VersionBumpMinor:
+		nums = [3]int{nums[0], nums[1] + 1, 0}
+	case VersionBumpPatch:
+		nums[2]++
+	default:
+		return "", fmt.Errorf("unknown RULE_VERSION_BUMP %q (expected major, minor, patch or none)", part)
+	}
+	return fmt.Sprintf("%s%d.%d.%d", prefix, nums[0], nums[1], nums[2]), nil
+}
+
+// sameRuleSet compares JSON encodings, since patched condition values come back
+// as float64 where the YAML held ints
+func sameRuleSet(a, b *models.RuleSet) bool {
+	aJSON, aErr := json.Marshal(a)
+	bJSON, bErr := json.Marshal(b)
+	return aErr == nil && bErr == nil && bytes.Equal(aJSON, bJSON)
+}
+
+func findRule(ruleSet *models.RuleSet, ruleID string) int {
+	for i, rule := range ruleSet.Rules {
+		if rule.RuleID == ruleID {
+			return i
+		}
+	}
+	return -1
+}
+
+// checkRuleID fills in rule_id from the path and rejects a body naming another rule
+func checkRuleID(ruleID string, rule *models.Rule) error {
+	if rule.RuleID == "" {
+		rule.RuleID = ruleID
+	}
+	if rule.RuleID != ruleID {
+		return fmt.Errorf("%w: rule_id %q in body does not match %q in path", ErrInvalidRuleSet, rule.RuleID, ruleID)
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package services
+
+import (
+	"admin_server/backend/internal/models"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// engineStatusStore keeps the last report of every rule engine instance
+type engineStatusStore interface {
+	Put(ctx context.Context, status models.EngineStatus) error
+	// List returns every instance, sorted by instance name
+	List(ctx context.Context) ([]models.EngineStatus, error)
+}
+
+// redisEngineStatusStore keeps one JSON entry per instance in a hash
+type redisEngineStatusStore struct {
+	client *redis.Client
+	key    string
+}
+
+func newRedisEngineStatusStore(client *redis.Client, key string) *redisEngineStatusStore {
+	return &redisEngineStatusStore{client: client, key: key}
+}
+
+func (r *redisEngineStatusStore) Put(ctx context.Context, status models.EngineStatus) error {
+	statusJSON, err := json.Marshal(status)
+	if err != nil {
+		return fmt.Errorf("failed to marshal engine status: %w", err)
+	}
+	if err := r.client.HSet(ctx, r.key, status.Instance, statusJSON).Err(); err != nil {
+		return fmt.Errorf("failed to store engine status: %w", err)
+	}
+	return nil
+}
+
+func (r *redisEngineStatusStore) List(ctx context.Context) ([]models.EngineStatus, error) {
+	values, err := r.client.HGetAll(ctx, r.key).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read engine statuses from Redis: %w", err)
+	}
+
+	statuses := make([]models.EngineStatus, 0, len(values))
+	for instance, raw := range values {
+		var status models.EngineStatus
+		if err := json.Unmarshal([]byte(raw), &status); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal engine status %s: %w", instance, err)
+		}
+		statuses = append(statuses, status)
+	}
+	sortEngineStatuses(statuses)
+	return statuses, nil
+}
+
+// memoryEngineStatusStore keeps engine statuses in process memory (local development only)
+type memoryEngineStatusStore struct {
+	mu       sync.RWMutex
+	statuses map[string]models.EngineStatus
+}
+
+func newMemoryEngineStatusStore() *memoryEngineStatusStore {
+	return &memoryEngineStatusStore{statuses: make(map[string]models.EngineStatus)}
+}
+
+func (m *memoryEngineStatusStore) Put(ctx context.Context, status models.EngineStatus) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.statuses[status.Instance] = status
+	return nil
+}
+
+func (m *memoryEngineStatusStore) List(ctx context.Context) ([]models.EngineStatus, error) {
+	m.mu.RLock()
+	statuses := make([]models.EngineStatus, 0, len(m.statuses))
+	for _, status := range m.statuses {
+		statuses = append(statuses, status)
+	}
+	m.mu.RUnlock()
+
+	sortEngineStatuses(statuses)
+	return statuses, nil
+}
+
+func sortEngineStatuses(statuses []models.EngineStatus) {
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Instance < statuses[j].Instance
+	})
+}
@@ -0,0 +1,55 @@
+package services
+
+import (
+	"admin_server/backend/internal/models"
+	"slices"
+)
+
+// RuleFilter selects which rules GET /rules returns. Zero-valued fields match everything.
+type RuleFilter struct {
+	Enabled    *bool
+	Severities []string // any of these (case-insensitive)
+	Tags       []string // rules carrying any of these tags
+	Actions    []string // any of these; a rule without action counts as alert
+	Owner      string
+}
+
+// IsZero reports whether the filter matches every rule
+func (f RuleFilter) IsZero() bool {
+	return f.Enabled == nil && len(f.Severities) == 0 && len(f.Tags) == 0 && len(f.Actions) == 0 && f.Owner == ""
+}
+
+// Matches reports whether rule passes the filter
+func (f RuleFilter) Matches(rule models.Rule) bool {
+	if f.Enabled != nil && rule.IsEnabled() != *f.Enabled {
+		return false
+	}
+	if len(f.Severities) > 0 && !containsFold(f.Severities, rule.Severity) {
+		return false
+	}
+	if len(f.Tags) > 0 && !slices.ContainsFunc(f.Tags, func(tag string) bool { return slices.Contains(rule.Tags, tag) }) {
+		return false
+	}
+	if len(f.Actions) > 0 && !containsFold(f.Actions, rule.EffectiveAction()) {
+		return false
+	}
+	if f.Owner != "" && rule.Owner != f.Owner {
+		return false
+	}
+	return true
+}
+
+// FilterRules returns ruleSet with only the rules matching filter
+func FilterRules(ruleSet *models.RuleSet, filter RuleFilter) *models.RuleSet {
+	if filter.IsZero() {
+		return ruleSet
+	}
+	filtered := *ruleSet
+	filtered.Rules = make([]models.Rule, 0, len(ruleSet.Rules))
+	for _, rule := range ruleSet.Rules {
+		if filter.Matches(rule) {
+			filtered.Rules = append(filtered.Rules, rule)
+		}
+	}
+	return &filtered
+}
@@ -0,0 +1,175 @@
+package services
+
+import (
+	"admin_server/backend/internal/models"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ErrRevisionNotFound is returned when no rule revision has the requested number
+var ErrRevisionNotFound = errors.New("rule revision not found")
+
+// ruleHistoryStore keeps snapshots of replaced RuleSets
+type ruleHistoryStore interface {
+	// Add assigns the next revision number to revision and stores it
+	Add(ctx context.Context, revision *models.RuleRevision, maxLength int) error
+	// List returns every stored revision, newest first
+	List(ctx context.Context) ([]models.RuleRevision, error)
+	Get(ctx context.Context, rev int64) (*models.RuleRevision, error)
+}
+
+// redisRuleHistoryStore numbers revisions with INCR and keeps them in a hash,
+// indexed by a sorted set scored by revision number
+type redisRuleHistoryStore struct {
+	client   *redis.Client
+	seqKey   string
+	indexKey string
+	dataKey  string
+}
+
+func newRedisRuleHistoryStore(client *redis.Client, prefix string) *redisRuleHistoryStore {
+	return &redisRuleHistoryStore{
+		client:   client,
+		seqKey:   prefix + ":seq",
+		indexKey: prefix + ":index",
+		dataKey:  prefix + ":data",
+	}
+}
+
+func (r *redisRuleHistoryStore) Add(ctx context.Context, revision *models.RuleRevision, maxLength int) error {
+	rev, err := r.client.Incr(ctx, r.seqKey).Result()
+	if err != nil {
+		return fmt.Errorf("failed to allocate rule revision: %w", err)
+	}
+	revision.Revision = rev
+
+	revisionJSON, err := json.Marshal(revision)
+	if err != nil {
+		return fmt.Errorf("failed to marshal rule revision: %w", err)
+	}
+	field := strconv.FormatInt(rev, 10)
+
+	_, err = r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, r.dataKey, field, revisionJSON)
+		pipe.ZAdd(ctx, r.indexKey, redis.Z{Score: float64(rev), Member: field})
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to store rule revision: %w", err)
+	}
+
+	// Trimming is best effort; the new revision is already stored
+	if maxLength > 0 {
+		old, err := r.client.ZRange(ctx, r.indexKey, 0, int64(-maxLength-1)).Result()
+		if err != nil || len(old) == 0 {
+			return nil
+		}
+		members := make([]interface{}, len(old))
+		for i, id := range old {
+			members[i] = id
+		}
+		r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.ZRem(ctx, r.indexKey, members...)
+			pipe.HDel(ctx, r.dataKey, old...)
+			return nil
+		})
+	}
+	return nil
+}
+
+func (r *redisRuleHistoryStore) List(ctx context.Context) ([]models.RuleRevision, error) {
+	fields, err := r.client.ZRevRange(ctx, r.indexKey, 0, -1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rule history index: %w", err)
+	}
+	revisions := make([]models.RuleRevision, 0, len(fields))
+	if len(fields) == 0 {
+		return revisions, nil
+	}
+
+	values, err := r.client.HMGet(ctx, r.dataKey, fields...).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rule history: %w", err)
+	}
+	for i, value := range values {
+		raw, ok := value.(string)
+		if !ok {
+			continue
+		}
+		var revision models.RuleRevision
+		if err := json.Unmarshal([]byte(raw), &revision); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal rule revision %s: %w", fields[i], err)
+		}
+		revisions = append(revisions, revision)
+	}
+	return revisions, nil
+}
+
+func (r *redisRuleHistoryStore) Get(ctx context.Context, rev int64) (*models.RuleRevision, error) {
+	raw, err := r.client.HGet(ctx, r.dataKey, strconv.FormatInt(rev, 10)).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrRevisionNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rule revision: %w", err)
+	}
+	var revision models.RuleRevision
+	if err := json.Unmarshal([]byte(raw), &revision); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal rule revision %d: %w", rev, err)
+	}
+	return &revision, nil
+}
+
+// memoryRuleHistoryStore keeps revisions in process memory (local development only)
+type memoryRuleHistoryStore struct {
+	mu        sync.RWMutex
+	seq       int64
+	revisions []models.RuleRevision // oldest first
+}
+
+func newMemoryRuleHistoryStore() *memoryRuleHistoryStore {
+	return &memoryRuleHistoryStore{}
+}
+
+func (m *memoryRuleHistoryStore) Add(ctx context.Context, revision *models.RuleRevision, maxLength int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.seq++
+	revision.Revision = m.seq
+	m.revisions = append(m.revisions, *revision)
+	if maxLength > 0 && len(m.revisions) > maxLength {
+		m.revisions = m.revisions[len(m.revisions)-maxLength:]
+	}
+	return nil
+}
+
+func (m *memoryRuleHistoryStore) List(ctx context.Context) ([]models.RuleRevision, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	revisions := make([]models.RuleRevision, 0, len(m.revisions))
+	for i := len(m.revisions) - 1; i >= 0; i-- {
+		revisions = append(revisions, m.revisions[i])
+	}
+	return revisions, nil
+}
+
+func (m *memoryRuleHistoryStore) Get(ctx context.Context, rev int64) (*models.RuleRevision, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, revision := range m.revisions {
+		if revision.Revision == rev {
+			found := revision
+			return &found, nil
+		}
+	}
+	return nil, ErrRevisionNotFound
+}
@@ -0,0 +1,165 @@
+package services
+
+import (
+	"admin_server/backend/internal/converter"
+	"admin_server/backend/internal/evaluator"
+	"admin_server/backend/internal/models"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Import modes
+const (
+	ImportModeMerge   = "merge"   // upsert the imported rules by rule_id
+	ImportModeReplace = "replace" // the imported rules replace the whole RuleSet
+)
+
+// MaxImportBytes caps an uploaded rule file; a ConfigMap holds at most 1 MiB anyway
+const MaxImportBytes = 1 << 20
+
+// ErrInvalidImport is returned for unreadable, oversized or empty rule files and
+// unknown import or export formats
+var ErrInvalidImport = errors.New("invalid rule import")
+
+// ExportRules returns the live RuleSet encoded as yaml (the ConfigMap's format) or
+// json, along with the RuleSet and the ConfigMap resourceVersion
+func (s *RuleService) ExportRules(format string) ([]byte, *models.RuleSet, string, error) {
+	ruleSet, resourceVersion, err := s.GetRules()
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	var data []byte
+	switch format {
+	case converter.FormatYAML:
+		data, err = yaml.Marshal(ruleSet)
+	case converter.FormatJSON:
+		data, err = json.MarshalIndent(ruleSet, "", "  ")
+	default:
+		return nil, nil, "", fmt.Errorf("%w: export format %q (supported: yaml, json)", ErrInvalidImport, format)
+	}
+	if err != nil {
+		return nil, nil, "", fmt.Errorf("failed to encode rules: %w", err)
+	}
+	return data, ruleSet, resourceVersion, nil
+}
+
+// ReadImportFile reads an uploaded rule file of at most MaxImportBytes
+func ReadImportFile(r io.Reader) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(r, MaxImportBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidImport, err)
+	}
+	if len(data) > MaxImportBytes {
+		return nil, fmt.Errorf("%w: file exceeds %d bytes", ErrInvalidImport, MaxImportBytes)
+	}
+	return data, nil
+}
+
+// ImportRules converts data (format detected when empty) and merges it into, or lets it
+// replace, the live RuleSet. The write goes through the same path as the per-rule edits:
+// validation, embedded tests, version bump and conflict retries. A dry run reports the
+// diff without writing.
+func (s *RuleService) ImportRules(data []byte, format, mode string, dryRun bool, expectedVersion, author string, strict bool) (*models.ImportRulesResponse, error) {
+	if mode == "" {
+		mode = ImportModeMerge
+	}
+	if mode != ImportModeMerge && mode != ImportModeReplace {
+		return nil, fmt.Errorf("%w: mode %q (expected %s or %s)", ErrInvalidImport, mode, ImportModeMerge, ImportModeReplace)
+	}
+
+	converted, format, err := converter.Convert(data, format)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidImport, err)
+	}
+	if len(converted.RuleSet.Rules) == 0 {
+		return nil, fmt.Errorf("%w: no rules could be read from the %s file", ErrInvalidImport, format)
+	}
+
+	response := &models.ImportRulesResponse{
+		Format:       format,
+		Mode:         mode,
+		DryRun:       dryRun,
+		Imported:     converted.RuleSet,
+		Untranslated: converted.Untranslated,
+	}
+	if response.Untranslated == nil {
+		response.Untranslated = []models.UntranslatedConstruct{}
+	}
+
+	if dryRun {
+		current, _, err := s.GetRules()
+		if err != nil {
+			return nil, err
+		}
+		proposed := cloneRuleSet(current)
+		applyImport(proposed, converted.RuleSet, mode)
+		// 전개할 수 없는 템플릿 룰은 expandRuleSet처럼 검증 오류로 응답
+		problems, err := s.applyTemplates(proposed)
+		if err != nil {
+			return nil, err
+		}
+		if len(problems) > 0 {
+			return nil, &RuleValidationError{Problems: problems}
+		}
+		if proposed.RulesetVersion == current.RulesetVersion && !sameRuleSet(proposed, current) {
+			if bumped, err := bumpVersion(current.RulesetVersion, s.cfg.RuleVersionBump); err == nil {
+				proposed.RulesetVersion = bumped
+			}
+		}
+		if _, err := s.ValidateRules(proposed, strict); err != nil {
+			return nil, err
+		}
+		if report := evaluator.RunTests(proposed); report.Failed > 0 {
+			return nil, &RuleTestError{Report: report}
+		}
+		diff := DiffRuleSets(current, proposed)
+		response.Diff = &diff
+		return response, nil
+	}
+
+	var before, after *models.RuleSet
+	result, err := s.editRules(expectedVersion, author, strict, func(ruleSet *models.RuleSet) error {
+		before, after = cloneRuleSet(ruleSet), ruleSet
+		applyImport(ruleSet, converted.RuleSet, mode)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	// after는 editRules가 버전을 올린 뒤의 룰셋을 가리킨다
+	diff := DiffRuleSets(before, after)
+	response.Diff = &diff
+	response.Result = result
+
+	log.Printf("Imported %d %s rules (%s, %d untranslated) by %s", len(converted.RuleSet.Rules), format, mode, len(converted.Untranslated), author)
+	return response, nil
+}
+
+// applyImport merges imported into ruleSet by rule_id, or replaces its rules (and the
+// description and ruleset_version, when the file sets them)
+func applyImport(ruleSet, imported *models.RuleSet, mode string) {
+	if mode == ImportModeReplace {
+		ruleSet.Rules = cloneRuleSet(imported).Rules
+		if imported.Description != "" {
+			ruleSet.Description = imported.Description
+		}
+		if imported.RulesetVersion != "" {
+			ruleSet.RulesetVersion = imported.RulesetVersion
+		}
+		return
+	}
+
+	for _, rule := range cloneRuleSet(imported).Rules {
+		if i := findRule(ruleSet, rule.RuleID); i >= 0 {
+			ruleSet.Rules[i] = rule
+		} else {
+			ruleSet.Rules = append(ruleSet.Rules, rule)
+		}
+	}
+}
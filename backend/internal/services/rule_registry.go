@@ -0,0 +1,128 @@
+package services
+
+import (
+	"admin_server/backend/internal/config"
+	"admin_server/backend/internal/models"
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// DefaultRuleset names the ruleset configured by NAMESPACE, CONFIG_MAP_NAME and
+// CONFIG_MAP_KEY, which the unscoped /api/v1/rules endpoints manage
+const DefaultRuleset = "default"
+
+// ErrRulesetNotFound is returned for a ruleset name that is not registered
+var ErrRulesetNotFound = errors.New("ruleset not found")
+
+var rulesetNamePattern = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+
+// rulesetRegistry holds one RuleService per registered ruleset, in RULESETS order
+// after the default
+type rulesetRegistry struct {
+	names    []string
+	services map[string]*RuleService
+}
+
+// Ruleset returns the RuleService scoped to the named ruleset
+func (s *RuleService) Ruleset(name string) (*RuleService, error) {
+	scoped, ok := s.registry.services[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrRulesetNotFound, name)
+	}
+	return scoped, nil
+}
+
+// ListRulesets reports every registered ruleset with the RuleSet it holds
+func (s *RuleService) ListRulesets() *models.RulesetsResponse {
+	response := &models.RulesetsResponse{Rulesets: make([]models.RulesetStatus, 0, len(s.registry.names))}
+	for _, name := range s.registry.names {
+		response.Rulesets = append(response.Rulesets, s.registry.services[name].Status())
+	}
+	return response
+}
+
+// Status describes this ruleset and the RuleSet it holds. A ConfigMap that cannot be
+// read is reported in Error, so one broken ruleset does not fail the whole list.
+func (s *RuleService) Status() models.RulesetStatus {
+	status := models.RulesetStatus{Ruleset: s.ruleset}
+	ruleSet, resourceVersion, err := s.GetRules()
+	if err != nil {
+		status.Error = err.Error()
+		return status
+	}
+	status.RulesetVersion = ruleSet.RulesetVersion
+	status.ResourceVersion = resourceVersion
+	status.RuleCount = len(ruleSet.Rules)
+	return status
+}
+
+// rulesPath is the API path of this ruleset's rule endpoints
+func (s *RuleService) rulesPath() string {
+	if s.ruleset.Default {
+		return "/api/v1/rules"
+	}
+	return "/api/v1/rulesets/" + s.ruleset.Name + "/rules"
+}
+
+// StartWatch runs the rule ConfigMap informers that back GetRules and rule change
+// events, one per registered ruleset
+func (s *RuleService) StartWatch(ctx context.Context) {
+	for _, name := range s.registry.names {
+		s.registry.services[name].watcher.Start(ctx)
+	}
+}
+
+// parseRulesets returns the default ruleset followed by the RULESETS entries
+// ("name=namespace/configmap[/key]"). The candidate ConfigMap of a registered
+// ruleset is its ConfigMap name with a "-candidate" suffix.
+func parseRulesets(cfg *config.Config) ([]models.Ruleset, error) {
+	rulesets := []models.Ruleset{{
+		Name:               DefaultRuleset,
+		Namespace:          cfg.Namespace,
+		ConfigMap:          cfg.ConfigMapName,
+		Key:                cfg.ConfigMapKey,
+		CandidateConfigMap: cfg.CandidateConfigMapName,
+		Default:            true,
+	}}
+	seen := map[string]bool{DefaultRuleset: true}
+
+	for _, entry := range cfg.Rulesets {
+		name, location, ok := strings.Cut(entry, "=")
+		parts := strings.Split(location, "/")
+		if !ok || len(parts) < 2 || len(parts) > 3 || parts[0] == "" || parts[1] == "" {
+			return nil, fmt.Errorf("invalid RULESETS entry %q (expected name=namespace/configmap[/key])", entry)
+		}
+		if !rulesetNamePattern.MatchString(name) {
+			return nil, fmt.Errorf("invalid ruleset name %q (lowercase letters, digits and '-')", name)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("duplicate ruleset name %q in RULESETS", name)
+		}
+		seen[name] = true
+
+		ruleset := models.Ruleset{
+			Name:               name,
+			Namespace:          parts[0],
+			ConfigMap:          parts[1],
+			Key:                cfg.ConfigMapKey,
+			CandidateConfigMap: parts[1] + "-candidate",
+		}
+		if len(parts) == 3 && parts[2] != "" {
+			ruleset.Key = parts[2]
+		}
+		rulesets = append(rulesets, ruleset)
+	}
+	return rulesets, nil
+}
+
+// rulesetRedisKey keeps the default ruleset on the configured key, so existing
+// history and engine reports stay visible, and suffixes the others with their name
+func rulesetRedisKey(key string, ruleset models.Ruleset) string {
+	if ruleset.Default {
+		return key
+	}
+	return key + ":" + ruleset.Name
+}
@@ -0,0 +1,334 @@
+package services
+
+import (
+	"admin_server/backend/internal/evaluator"
+	"admin_server/backend/internal/models"
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Condition field value kinds
+const (
+	FieldKindString = "string"
+	FieldKindInt    = "int"
+	FieldKindAny    = "any" // string or number, e.g. raw syscall arguments
+)
+
+// FieldSpec describes a field a Condition may test
+type FieldSpec struct {
+	Kind        string
+	Description string
+	Syscall     bool // value is a syscall name
+}
+
+// FieldCatalogue lists the event fields the rule engine exposes to conditions.
+// Syscall arguments are addressed as args.0 … args.5 (see argFieldPattern).
+var FieldCatalogue = map[string]FieldSpec{
+	"syscall_name":   {Kind: FieldKindString, Description: "syscall name, e.g. openat", Syscall: true},
+	"syscall_nr":     {Kind: FieldKindInt, Description: "syscall number"},
+	"return_value":   {Kind: FieldKindInt, Description: "syscall return value"},
+	"comm":           {Kind: FieldKindString, Description: "process command name"},
+	"exe":            {Kind: FieldKindString, Description: "executable path"},
+	"path":           {Kind: FieldKindString, Description: "file path argument"},
+	"pid":            {Kind: FieldKindInt, Description: "process ID"},
+	"ppid":           {Kind: FieldKindInt, Description: "parent process ID"},
+	"uid":            {Kind: FieldKindInt, Description: "user ID"},
+	"gid":            {Kind: FieldKindInt, Description: "group ID"},
+	"container_id":   {Kind: FieldKindString, Description: "container ID"},
+	"container_name": {Kind: FieldKindString, Description: "container name"},
+	"image":          {Kind: FieldKindString, Description: "container image"},
+	"pod_name":       {Kind: FieldKindString, Description: "pod name"},
+	"namespace":      {Kind: FieldKindString, Description: "pod namespace"},
+}
+
+// MaxConditionDepth bounds how deeply condition groups nest; a rule's condition
+// list is level 1, so a group directly in it puts its children at level 2
+const MaxConditionDepth = 8
+
+var argFieldPattern = regexp.MustCompile(`^args\.[0-5]$`)
+
+// LookupField returns the spec of a catalogue field or syscall argument
+func LookupField(field string) (FieldSpec, bool) {
+	if spec, ok := FieldCatalogue[field]; ok {
+		return spec, true
+	}
+	if argFieldPattern.MatchString(field) {
+		return FieldSpec{Kind: FieldKindAny, Description: "syscall argument"}, true
+	}
+	return FieldSpec{}, false
+}
+
+// Condition operators, evaluated by the evaluator package
+const (
+	OpEquals    = evaluator.OpEquals
+	OpNotEquals = evaluator.OpNotEquals
+	OpIn        = evaluator.OpIn
+	OpNotIn     = evaluator.OpNotIn
+	OpPrefix    = evaluator.OpPrefix
+	OpSuffix    = evaluator.OpSuffix
+	OpContains  = evaluator.OpContains
+	OpRegex     = evaluator.OpRegex
+	OpGT        = evaluator.OpGT
+	OpGTE       = evaluator.OpGTE
+	OpLT        = evaluator.OpLT
+	OpLTE       = evaluator.OpLTE
+)
+
+// operatorKinds lists the field kinds each operator applies to
+var operatorKinds = map[string][]string{
+	OpEquals:    {FieldKindString, FieldKindInt, FieldKindAny},
+	OpNotEquals: {FieldKindString, FieldKindInt, FieldKindAny},
+	OpIn:        {FieldKindString, FieldKindInt, FieldKindAny},
+	OpNotIn:     {FieldKindString, FieldKindInt, FieldKindAny},
+	OpPrefix:    {FieldKindString, FieldKindAny},
+	OpSuffix:    {FieldKindString, FieldKindAny},
+	OpContains:  {FieldKindString, FieldKindAny},
+	OpRegex:     {FieldKindString, FieldKindAny},
+	OpGT:        {FieldKindInt, FieldKindAny},
+	OpGTE:       {FieldKindInt, FieldKindAny},
+	OpLT:        {FieldKindInt, FieldKindAny},
+	OpLTE:       {FieldKindInt, FieldKindAny},
+}
+
+// Operators returns the supported operator names, sorted
+func Operators() []string {
+	ops := make([]string, 0, len(operatorKinds))
+	for op := range operatorKinds {
+		ops = append(ops, op)
+	}
+	sort.Strings(ops)
+	return ops
+}
+
+// RuleValidationError carries every problem found in a RuleSet
+type RuleValidationError struct {
+	Problems []models.ValidationProblem
+	Warnings []models.ValidationProblem
+}
+
+func (e *RuleValidationError) Error() string {
+	if len(e.Problems) == 1 {
+		return fmt.Sprintf("ruleset is invalid: %s: %s", e.Problems[0].Pointer, e.Problems[0].Message)
+	}
+	return fmt.Sprintf("ruleset is invalid: %d problems", len(e.Problems))
+}
+
+// ruleValidator collects problems instead of stopping at the first one
+type ruleValidator struct {
+	problems []models.ValidationProblem
+	// templated는 템플릿 조건을 검사할 때 켜며, 플레이스홀더가 있는 조건은 연산자만 확인한다
+	templated bool
+}
+
+func (v *ruleValidator) addf(pointer, format string, args ...interface{}) {
+	v.problems = append(v.problems, models.ValidationProblem{
+		Pointer: pointer,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+func (v *ruleValidator) validateRuleSet(ruleSet *models.RuleSet) {
+	if ruleSet.RulesetVersion == "" {
+		v.addf("/ruleset_version", "ruleset_version is required")
+	}
+	if len(ruleSet.Rules) == 0 {
+		v.addf("/rules", "at least one rule is required")
+	}
+
+	seen := make(map[string]int, len(ruleSet.Rules))
+	for i, rule := range ruleSet.Rules {
+		pointer := fmt.Sprintf("/rules/%d", i)
+		if rule.RuleID == "" {
+			v.addf(pointer+"/rule_id", "rule_id is required")
+		} else if first, ok := seen[rule.RuleID]; ok {
+			v.addf(pointer+"/rule_id", "duplicate rule_id %q (first used by /rules/%d)", rule.RuleID, first)
+		} else {
+			seen[rule.RuleID] = i
+		}
+		v.validateRule(pointer, rule)
+	}
+}
+
+func (v *ruleValidator) validateRule(pointer string, rule models.Rule) {
+	if rule.Severity != "" && models.SeverityRank(rule.Severity) < 0 {
+		v.addf(pointer+"/severity", "unknown severity %q (supported: %s)", rule.Severity, strings.Join(models.SeverityLevels, ", "))
+	}
+	if rule.Action != "" && !slices.Contains(models.RuleActions, rule.Action) {
+		v.addf(pointer+"/action", "unknown action %q (supported: %s)", rule.Action, strings.Join(models.RuleActions, ", "))
+	}
+	seenTags := make(map[string]bool, len(rule.Tags))
+	for i, tag := range rule.Tags {
+		tagPointer := fmt.Sprintf("%s/tags/%d", pointer, i)
+		switch {
+		case strings.TrimSpace(tag) == "":
+			v.addf(tagPointer, "tag must not be empty")
+		case strings.ContainsAny(tag, ", "):
+			v.addf(tagPointer, "tag %q must not contain commas or spaces", tag)
+		case seenTags[tag]:
+			v.addf(tagPointer, "duplicate tag %q", tag)
+		}
+		seenTags[tag] = true
+	}
+	// 템플릿 룰의 조건은 전개 결과이며, 전개 실패는 이미 문제로 보고됨
+	if len(rule.Conditions) == 0 && rule.Template == "" {
+		v.addf(pointer+"/conditions", "at least one condition is required")
+	}
+	for i, test := range rule.Tests {
+		testPointer := fmt.Sprintf("%s/tests/%d", pointer, i)
+		if len(test.Event) == 0 {
+			v.addf(testPointer+"/event", "test event must not be empty")
+		}
+		if test.Expect != models.RuleTestMatch && test.Expect != models.RuleTestNoMatch {
+			v.addf(testPointer+"/expect", "expect must be %q or %q", models.RuleTestMatch, models.RuleTestNoMatch)
+		}
+	}
+	for i, cond := range rule.Conditions {
+		v.validateCondition(fmt.Sprintf("%s/conditions/%d", pointer, i), cond, 1)
+	}
+}
+
+// validateCondition checks a leaf, or a group and its children. depth counts the
+// rule's own condition list as 1.
+func (v *ruleValidator) validateCondition(pointer string, cond models.Condition, depth int) {
+	if cond.IsGroup() {
+		v.validateGroup(pointer, cond, depth)
+		return
+	}
+	if v.templated && len(placeholders(cond)) > 0 {
+		if _, ok := operatorKinds[cond.Operator]; !ok {
+			v.addf(pointer+"/operator", "unknown operator %q (supported: %s)", cond.Operator, strings.Join(Operators(), ", "))
+		}
+		return
+	}
+
+	spec, ok := LookupField(cond.Field)
+	if !ok {
+		v.addf(pointer+"/field", "unknown field %q", cond.Field)
+	}
+	kinds, opOK := operatorKinds[cond.Operator]
+	if !opOK {
+		v.addf(pointer+"/operator", "unknown operator %q (supported: %s)", cond.Operator, strings.Join(Operators(), ", "))
+	}
+	if !ok || !opOK {
+		return
+	}
+	if !slices.Contains(kinds, spec.Kind) {
+		v.addf(pointer+"/operator", "operator %q does not apply to %s field %q", cond.Operator, spec.Kind, cond.Field)
+		return
+	}
+
+	valuePointer := pointer + "/value"
+	switch cond.Operator {
+	case OpIn, OpNotIn:
+		items, ok := cond.Value.([]interface{})
+		if !ok || len(items) == 0 {
+			v.addf(valuePointer, "operator %q requires a non-empty list", cond.Operator)
+			return
+		}
+		for i, item := range items {
+			if msg := checkValueKind(item, spec.Kind); msg != "" {
+				v.addf(fmt.Sprintf("%s/%d", valuePointer, i), "%s", msg)
+			}
+		}
+	case OpPrefix, OpSuffix, OpContains:
+		if s, ok := cond.Value.(string); !ok || s == "" {
+			v.addf(valuePointer, "operator %q requires a non-empty string", cond.Operator)
+		}
+	case OpRegex:
+		s, ok := cond.Value.(string)
+		if !ok {
+			v.addf(valuePointer, "operator %q requires a string", cond.Operator)
+			return
+		}
+		if _, err := regexp.Compile(s); err != nil {
+			v.addf(valuePointer, "invalid regex: %v", err)
+		}
+	case OpGT, OpGTE, OpLT, OpLTE:
+		if _, ok := evaluator.Number(cond.Value); !ok {
+			v.addf(valuePointer, "operator %q requires a number", cond.Operator)
+		}
+	default:
+		if msg := checkValueKind(cond.Value, spec.Kind); msg != "" {
+			v.addf(valuePointer, "%s", msg)
+		}
+	}
+}
+
+func (v *ruleValidator) validateGroup(pointer string, cond models.Condition, depth int) {
+	kinds := 0
+	for _, set := range []bool{cond.All != nil, cond.Any != nil, cond.Not != nil} {
+		if set {
+			kinds++
+		}
+	}
+	if kinds > 1 || cond.IsLeaf() {
+		v.addf(pointer, "a condition is either a field test or exactly one of all, any, not")
+		return
+	}
+	if depth >= MaxConditionDepth {
+		v.addf(pointer, "condition groups are nested deeper than %d levels", MaxConditionDepth)
+		return
+	}
+
+	kind := cond.GroupKind()
+	if kind == models.ConditionNot {
+		v.validateCondition(pointer+"/not", *cond.Not, depth+1)
+		return
+	}
+	children := cond.All
+	if kind == models.ConditionAny {
+		children = cond.Any
+	}
+	if len(children) == 0 {
+		v.addf(pointer+"/"+kind, "%s group needs at least one condition", kind)
+	}
+	for i, child := range children {
+		v.validateCondition(fmt.Sprintf("%s/%s/%d", pointer, kind, i), child, depth+1)
+	}
+}
+
+// walkConditions calls fn for every leaf condition under conditions, with its JSON pointer
+func walkConditions(pointer string, conditions []models.Condition, fn func(pointer string, cond models.Condition)) {
+	for i, cond := range conditions {
+		walkCondition(fmt.Sprintf("%s/%d", pointer, i), cond, fn)
+	}
+}
+
+func walkCondition(pointer string, cond models.Condition, fn func(pointer string, cond models.Condition)) {
+	if !cond.IsGroup() {
+		fn(pointer, cond)
+		return
+	}
+	walkConditions(pointer+"/all", cond.All, fn)
+	walkConditions(pointer+"/any", cond.Any, fn)
+	if cond.Not != nil {
+		walkCondition(pointer+"/not", *cond.Not, fn)
+	}
+}
+
+// checkValueKind returns a problem message when value does not fit a field of kind
+func checkValueKind(value interface{}, kind string) string {
+	switch kind {
+	case FieldKindString:
+		if s, ok := value.(string); !ok || s == "" {
+			return "expected a non-empty string"
+		}
+	case FieldKindInt:
+		n, ok := evaluator.Number(value)
+		if !ok || n != math.Trunc(n) {
+			return "expected an integer"
+		}
+	case FieldKindAny:
+		if _, ok := value.(string); ok {
+			return ""
+		}
+		if _, ok := evaluator.Number(value); !ok {
+			return "expected a string or number"
+		}
+	}
+	return ""
+}
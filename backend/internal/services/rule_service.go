@@ -2,63 +2,177 @@ package services
 
 import (
 	"admin_server/backend/internal/config"
+	"admin_server/backend/internal/evaluator"
 	"admin_server/backend/internal/models"
+	"admin_server/backend/internal/reloader"
+	"errors"
 	"fmt"
 	"log"
+	"time"
 
 	"context" // <-- [추가]
 
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	"github.com/redis/go-redis/v9"
 	"gopkg.in/yaml.v3"
 	"k8s.io/client-go/kubernetes"
 )
 
-// RuleService handles rule-related operations
+// AnyResourceVersion skips the optimistic concurrency check (If-Match: *)
+const AnyResourceVersion = "*"
+
+// ErrPreconditionRequired is returned when an update does not say which version it was based on
+var ErrPreconditionRequired = errors.New("If-Match header with the ETag from GET /api/v1/rules is required")
+
+// ErrInvalidRuleSet wraps validation failures of a RuleSet about to be written
+var ErrInvalidRuleSet = errors.New("invalid ruleset")
+
+// ErrInvalidEngineReport is returned for engine status reports without instance or ruleset_version
+var ErrInvalidEngineReport = errors.New("instance and ruleset_version are required")
+
+// RuleTestError is returned when a RuleSet about to be written fails its embedded tests
+type RuleTestError struct {
+	Report *models.RuleTestReport
+}
+
+func (e *RuleTestError) Error() string {
+	return fmt.Sprintf("%d of %d rule tests failed", e.Report.Failed, e.Report.Total)
+}
+
+// RuleConflictError is returned when the ConfigMap changed after the caller read it
+type RuleConflictError struct {
+	ResourceVersion string
+	Current         *models.RuleSet
+	Diff            models.RuleSetDiff // from Current to the rejected RuleSet
+}
+
+func (e *RuleConflictError) Error() string {
+	return fmt.Sprintf("rule ConfigMap was modified concurrently (current resourceVersion %s)", e.ResourceVersion)
+}
+
+// RuleService handles rule-related operations on one ruleset. The RuleService returned
+// by NewRuleService manages the default ruleset; Ruleset returns the others.
 type RuleService struct {
-	cfg *config.Config
+	cfg     *config.Config
+	ruleset models.Ruleset
 	// TODO: Add K8s client when implementing actual K8s integration
 	clientset kubernetes.Interface
+	history   ruleHistoryStore
+	syscalls  *SyscallService
+	templates *RuleTemplateService
+	reloader  *reloader.Manager
+	engines   engineStatusStore
+	watcher   *ruleWatcher
+	registry  *rulesetRegistry
 }
 
-func NewRuleService(cfg *config.Config, clientset kubernetes.Interface) *RuleService {
-	return &RuleService{
-		cfg:       cfg,
-		clientset: clientset,
+// NewRuleService creates a RuleService for every registered ruleset (see RULESETS) and
+// returns the default one. Replaced RuleSets are snapshotted to Redis (or process memory
+// when RULE_HISTORY_STORE=memory, which also applies to engine status reports), syscall
+// conditions are cross-checked against the callable syscalls known to syscalls, rules naming
+// a template are expanded from templates, and rule engines are told to reload through reload.
+func NewRuleService(cfg *config.Config, clientset kubernetes.Interface, redisClient *redis.Client, syscalls *SyscallService, templates *RuleTemplateService, reload *reloader.Manager) (*RuleService, error) {
+	rulesets, err := parseRulesets(cfg)
+	if err != nil {
+		return nil, err
 	}
+
+	registry := &rulesetRegistry{services: make(map[string]*RuleService, len(rulesets))}
+	for _, ruleset := range rulesets {
+		var history ruleHistoryStore
+		var engines engineStatusStore
+		if cfg.RuleHistoryStore == "memory" {
+			history = newMemoryRuleHistoryStore()
+			engines = newMemoryEngineStatusStore()
+		} else {
+			history = newRedisRuleHistoryStore(redisClient, rulesetRedisKey(cfg.RuleHistoryRedisKey, ruleset))
+			engines = newRedisEngineStatusStore(redisClient, rulesetRedisKey(cfg.RuleEngineRedisKey, ruleset))
+		}
+
+		registry.names = append(registry.names, ruleset.Name)
+		registry.services[ruleset.Name] = &RuleService{
+			cfg:       cfg,
+			ruleset:   ruleset,
+			clientset: clientset,
+			history:   history,
+			syscalls:  syscalls,
+			templates: templates,
+			reloader:  reload,
+			engines:   engines,
+			watcher:   newRuleWatcher(clientset, ruleset.Namespace, ruleset.ConfigMap, ruleset.Key, cfg.AlertStreamBuffer),
+			registry:  registry,
+		}
+	}
+	return registry.services[DefaultRuleset], nil
+}
+
+func (s *RuleService) SubscribeRuleEvents(lastEventID string) ([]models.RuleChangeEvent, *RuleEventSubscription) {
+	return s.watcher.Subscribe(lastEventID)
 }
 
-// GetRules retrieves the current rules directly from ConfigMap via K8s API
-func (s *RuleService) GetRules() (*models.RuleSet, error) {
-	// [수정] K8s API를 통해 ConfigMap 데이터를 직접 조회하여 즉각적인 반영을 보장합니다.
+// GetRules returns the current rules from the informer cache, along with the ConfigMap
+// resourceVersion used as the ETag. Until the cache has synced it reads the ConfigMap
+// directly via K8s API. A lagging cache can only hand out an older resourceVersion,
+// which UpdateRules rejects with a conflict, so it never causes a lost write.
+func (s *RuleService) GetRules() (*models.RuleSet, string, error) {
+	ruleSet, resourceVersion, err := s.watcher.Get()
+	if !errors.Is(err, errRuleCacheNotReady) {
+		return ruleSet, resourceVersion, err
+	}
+
+	// 캐시가 준비되지 않았으면 K8s API를 통해 ConfigMap 데이터를 직접 조회
 	log.Println("Getting rules from Kubernetes ConfigMap via API")
 
-	// 1. K8s API를 통해 ConfigMap의 현재 상태를 가져오기 (파일 읽기 로직 대체)
-	configMap, err := s.clientset.CoreV1().ConfigMaps(s.cfg.Namespace).Get(context.TODO(), s.cfg.ConfigMapName, metav1.GetOptions{})
+	configMap, ruleSet, err := s.getRuleConfigMap(s.ruleset.ConfigMap)
 	if err != nil {
-		log.Printf("Failed to get ConfigMap %s via API: %v", s.cfg.ConfigMapName, err)
-		return nil, fmt.Errorf("failed to get ConfigMap via K8s API: %w", err)
+		return nil, "", err
 	}
+	return ruleSet, configMap.ResourceVersion, nil
+}
 
-	// 2. ConfigMap의 Data 필드에서 'rule.yaml' 키의 값 추출
-	yamlContent, ok := configMap.Data["rule.yaml"]
-	if !ok {
-		return nil, fmt.Errorf("ConfigMap %s does not contain 'rule.yaml' key", s.cfg.ConfigMapName)
+// getRuleConfigMap fetches the named rule ConfigMap in the ruleset's namespace and parses
+// the ruleset's key
+func (s *RuleService) getRuleConfigMap(name string) (*corev1.ConfigMap, *models.RuleSet, error) {
+	// 1. K8s API를 통해 ConfigMap의 현재 상태를 가져오기 (파일 읽기 로직 대체)
+	configMap, err := s.clientset.CoreV1().ConfigMaps(s.ruleset.Namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		log.Printf("Failed to get ConfigMap %s via API: %v", name, err)
+		return nil, nil, fmt.Errorf("failed to get ConfigMap via K8s API: %w", err)
 	}
 
-	// 3. YAML 내용을 모델로 언마샬
-	var ruleSet models.RuleSet
-	err = yaml.Unmarshal([]byte(yamlContent), &ruleSet)
+	// 2. 룰셋 키(기본값: 'rule.yaml')의 YAML 내용을 모델로 언마샬
+	ruleSet, err := parseRuleConfigMap(configMap, s.ruleset.Key)
 	if err != nil {
-		log.Printf("Failed to unmarshal rule YAML from ConfigMap: %v", err)
-		return nil, fmt.Errorf("failed to unmarshal rule YAML: %w", err)
+		log.Printf("Failed to parse rules from ConfigMap %s: %v", name, err)
+		return nil, nil, err
 	}
 
-	return &ruleSet, nil
+	return configMap, ruleSet, nil
 }
 
-// UpdateRules updates the rules in ConfigMap
-func (s *RuleService) UpdateRules(ruleSet *models.RuleSet) (*models.UpdateRulesResponse, error) {
+// UpdateRules updates the rules in ConfigMap. expectedVersion is the resourceVersion
+// the caller based its edit on (If-Match); a mismatch yields *RuleConflictError.
+// Rules naming a template are written with the template's conditions expanded.
+// A RuleSet whose embedded tests fail is refused with *RuleTestError.
+// The replaced RuleSet is recorded in the revision history under author.
+func (s *RuleService) UpdateRules(ruleSet *models.RuleSet, expectedVersion, author string) (*models.UpdateRulesResponse, error) {
+	if expectedVersion == "" {
+		return nil, ErrPreconditionRequired
+	}
+	if author == "" {
+		author = "anonymous"
+	}
+	ruleSet, err := s.expandRuleSet(ruleSet)
+	if err != nil {
+		return nil, err
+	}
+	if report := evaluator.RunTests(ruleSet); report.Failed > 0 {
+		log.Printf("Refusing ruleset_version %s: %d of %d rule tests failed", ruleSet.RulesetVersion, report.Failed, report.Total)
+		return nil, &RuleTestError{Report: report}
+	}
 
 	// 1. Convert to YAML
 	yamlData, err := yaml.Marshal(ruleSet)
@@ -66,55 +180,248 @@ func (s *RuleService) UpdateRules(ruleSet *models.RuleSet) (*models.UpdateRulesR
 		return nil, fmt.Errorf("failed to marshal rules to YAML: %w", err)
 	}
 
-	log.Printf("Updating ConfigMap '%s' in namespace '%s'", s.cfg.ConfigMapName, s.cfg.Namespace)
+	log.Printf("Updating ConfigMap '%s' in namespace '%s'", s.ruleset.ConfigMap, s.ruleset.Namespace)
 
 	// 2. ConfigMap의 현재 상태를 K8s API에서 가져오기
 	// s.clientset을 사용하여 RuleService에 주입된 클라이언트에 접근합니다.
-	configMap, err := s.clientset.CoreV1().ConfigMaps(s.cfg.Namespace).Get(context.TODO(), s.cfg.ConfigMapName, metav1.GetOptions{})
+	configMap, current, err := s.getRuleConfigMap(s.ruleset.ConfigMap)
 
 	// 3. ConfigMap Get 실패 시 처리
 	if err != nil {
-		return nil, fmt.Errorf("failed to get ConfigMap %s: %w", s.cfg.ConfigMapName, err)
+		return nil, fmt.Errorf("failed to get ConfigMap %s: %w", s.ruleset.ConfigMap, err)
+	}
+
+	// 4. 호출자가 읽은 이후 다른 사람이 수정했다면 덮어쓰지 않고 충돌로 응답
+	if expectedVersion != AnyResourceVersion && configMap.ResourceVersion != expectedVersion {
+		return nil, s.conflict(configMap.ResourceVersion, current, ruleSet)
 	}
 
-	// 4. 데이터 업데이트
-	configMap.Data["rule.yaml"] = string(yamlData)
+	// 5. 데이터 업데이트
+	priorVersion := configMap.ResourceVersion
+	if configMap.Data == nil {
+		configMap.Data = map[string]string{}
+	}
+	configMap.Data[s.ruleset.Key] = string(yamlData)
+	// 변경 이벤트(GET /rules/events)에서 작성자를 알 수 있도록 기록
+	if configMap.Annotations == nil {
+		configMap.Annotations = map[string]string{}
+	}
+	configMap.Annotations[annotationUpdatedBy] = author
 
-	// 5. K8s API로 ConfigMap 업데이트
-	_, err = s.clientset.CoreV1().ConfigMaps(s.cfg.Namespace).Update(context.TODO(), configMap, metav1.UpdateOptions{})
+	// 6. K8s API로 ConfigMap 업데이트 (resourceVersion이 다르면 API 서버가 Conflict 반환)
+	updated, err := s.clientset.CoreV1().ConfigMaps(s.ruleset.Namespace).Update(context.TODO(), configMap, metav1.UpdateOptions{FieldManager: ruleFieldManager})
 	if err != nil {
+		if apierrors.IsConflict(err) {
+			log.Printf("ConfigMap %s changed between read and write: %v", s.ruleset.ConfigMap, err)
+			latestMap, latest, getErr := s.getRuleConfigMap(s.ruleset.ConfigMap)
+			if getErr != nil {
+				return nil, fmt.Errorf("failed to update ConfigMap via K8s API: %w", err)
+			}
+			return nil, s.conflict(latestMap.ResourceVersion, latest, ruleSet)
+		}
 		// API 호출 실패 시 로그를 남김 (이 로그가 콘솔에 찍히는지 확인해야 함)
 		log.Printf("ERROR: Failed to update ConfigMap via K8s API: %v", err)
 		return nil, fmt.Errorf("failed to update ConfigMap via K8s API: %w", err)
 	}
 
-	// TODO: Trigger rule engine and eBPF generator to reload rules
-	// ... (이후 룰 엔진 리로드 로직)
+	// 7. 교체된 이전 룰셋을 히스토리에 저장 (실패해도 업데이트 자체는 성공으로 처리)
+	s.recordRevision(current, priorVersion, author)
 
+	// 8. 룰 엔진에 리로드 알림 (실패해도 ConfigMap 업데이트는 유지하고 결과만 응답에 포함)
 	newVersion := ruleSet.RulesetVersion
+	reload := s.reloader.Reload(context.TODO(), models.RuleReloadEvent{
+		Ruleset:         s.ruleset.Name,
+		Namespace:       s.ruleset.Namespace,
+		ConfigMap:       s.ruleset.ConfigMap,
+		RulesetVersion:  newVersion,
+		ResourceVersion: updated.ResourceVersion,
+		Timestamp:       time.Now().UTC().Format(time.RFC3339),
+	})
 
 	return &models.UpdateRulesResponse{
-		Status:     "success",
-		Message:    "Rule.yaml ConfigMap updated successfully.",
-		NewVersion: newVersion,
+		Status:          "success",
+		Message:         "Rule.yaml ConfigMap updated successfully.",
+		NewVersion:      newVersion,
+		ResourceVersion: updated.ResourceVersion,
+		Reload:          reload,
 	}, nil
 }
 
-// ValidateRules validates the rule structure
-func (s *RuleService) ValidateRules(ruleSet *models.RuleSet) error {
-	if ruleSet.RulesetVersion == "" {
-		return fmt.Errorf("ruleset_version is required")
+func (s *RuleService) recordRevision(prior *models.RuleSet, resourceVersion, author string) {
+	revision := &models.RuleRevision{
+		RulesetVersion:  prior.RulesetVersion,
+		ResourceVersion: resourceVersion,
+		Author:          author,
+		Timestamp:       time.Now().UTC().Format(time.RFC3339),
+		RuleCount:       len(prior.Rules),
+		RuleSet:         prior,
+	}
+	if err := s.history.Add(context.TODO(), revision, s.cfg.RuleHistoryMaxLength); err != nil {
+		log.Printf("WARN: Failed to record rule revision: %v", err)
+		return
+	}
+	log.Printf("Recorded rule revision %d (ruleset_version %s) replaced by %s", revision.Revision, revision.RulesetVersion, author)
+}
+
+// GetRuleHistory lists rule revisions, newest first, without their RuleSets
+func (s *RuleService) GetRuleHistory() (*models.RuleHistoryResponse, error) {
+	revisions, err := s.history.List(context.TODO())
+	if err != nil {
+		return nil, err
+	}
+	for i := range revisions {
+		revisions[i].RuleSet = nil
+	}
+	return &models.RuleHistoryResponse{Revisions: revisions}, nil
+}
+
+// GetRuleRevision returns a single revision including its RuleSet
+func (s *RuleService) GetRuleRevision(rev int64) (*models.RuleRevision, error) {
+	return s.history.Get(context.TODO(), rev)
+}
+
+// DiffRuleRevision compares the live RuleSet to revision rev, i.e. what a rollback would change
+func (s *RuleService) DiffRuleRevision(rev int64) (*models.RuleSetDiff, error) {
+	revision, err := s.history.Get(context.TODO(), rev)
+	if err != nil {
+		return nil, err
+	}
+	_, current, err := s.getRuleConfigMap(s.ruleset.ConfigMap)
+	if err != nil {
+		return nil, err
+	}
+	diff := DiffRuleSets(current, revision.RuleSet)
+	return &diff, nil
+}
+
+// RollbackRules restores revision rev through the same validation and update path as PUT /rules
+func (s *RuleService) RollbackRules(rev int64, expectedVersion, author string) (*models.UpdateRulesResponse, error) {
+	revision, err := s.history.Get(context.TODO(), rev)
+	if err != nil {
+		return nil, err
+	}
+	warnings, err := s.ValidateRules(revision.RuleSet, false)
+	if err != nil {
+		return nil, fmt.Errorf("%w: revision %d: %w", ErrInvalidRuleSet, rev, err)
+	}
+
+	log.Printf("Rolling back rules to revision %d (ruleset_version %s)", rev, revision.RulesetVersion)
+	response, err := s.UpdateRules(revision.RuleSet, expectedVersion, author)
+	if err != nil {
+		return nil, err
+	}
+	response.Message = fmt.Sprintf("Rolled back to revision %d.", rev)
+	response.Warnings = warnings
+	return response, nil
+}
+
+// TestRules runs the tests embedded in ruleSet, or in the live RuleSet when nil
+func (s *RuleService) TestRules(ruleSet *models.RuleSet) (*models.RuleTestReport, error) {
+	if ruleSet == nil {
+		live, _, err := s.GetRules()
+		if err != nil {
+			return nil, err
+		}
+		ruleSet = live
+	}
+	ruleSet, err := s.expandRuleSet(ruleSet)
+	if err != nil {
+		return nil, err
+	}
+	return evaluator.RunTests(ruleSet), nil
+}
+
+// ReportEngineStatus records the ruleset a rule engine instance has loaded
+func (s *RuleService) ReportEngineStatus(report *models.EngineReport) error {
+	if report.Instance == "" || report.RulesetVersion == "" {
+		return ErrInvalidEngineReport
+	}
+	return s.engines.Put(context.TODO(), models.EngineStatus{
+		EngineReport: *report,
+		LastSeen:     time.Now().UTC().Format(time.RFC3339),
+	})
+}
+
+// GetEngineStatuses lists every reporting rule engine instance and whether it runs
+// the live ruleset_version
+func (s *RuleService) GetEngineStatuses() (*models.EnginesResponse, error) {
+	live, _, err := s.GetRules()
+	if err != nil {
+		return nil, err
 	}
-	if len(ruleSet.Rules) == 0 {
-		return fmt.Errorf("at least one rule is required")
+	statuses, err := s.engines.List(context.TODO())
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	for i := range statuses {
+		statuses[i].InSync = statuses[i].RulesetVersion == live.RulesetVersion
+		lastSeen, err := time.Parse(time.RFC3339, statuses[i].LastSeen)
+		statuses[i].Stale = err != nil || (s.cfg.RuleEngineStaleAfter > 0 && now.Sub(lastSeen) > s.cfg.RuleEngineStaleAfter)
+	}
+
+	return &models.EnginesResponse{
+		LiveVersion: live.RulesetVersion,
+		Engines:     statuses,
+	}, nil
+}
+
+func (s *RuleService) conflict(resourceVersion string, current, proposed *models.RuleSet) *RuleConflictError {
+	return &RuleConflictError{
+		ResourceVersion: resourceVersion,
+		Current:         current,
+		Diff:            DiffRuleSets(current, proposed),
 	}
-	for _, rule := range ruleSet.Rules {
-		if rule.RuleID == "" {
-			return fmt.Errorf("rule_id is required for all rules")
+}
+
+// ValidateRules checks the RuleSet against the condition schema (field catalogue,
+// operators, value types, regex syntax) and rejects duplicate rule_ids. Syscall
+// conditions are then cross-checked against cluster_callable_syscalls: unknown
+// syscalls are returned as warnings, or as problems when strict is set or
+// RULE_SYSCALL_CHECK=strict. Rules naming a template are checked as expanded.
+// Problems are reported together as a *RuleValidationError.
+func (s *RuleService) ValidateRules(ruleSet *models.RuleSet, strict bool) ([]models.ValidationProblem, error) {
+	v := &ruleValidator{}
+	if usesTemplates(ruleSet) {
+		ruleSet = cloneRuleSet(ruleSet)
+		problems, err := s.applyTemplates(ruleSet)
+		if err != nil {
+			return nil, err
 		}
-		if len(rule.Conditions) == 0 {
-			return fmt.Errorf("at least one condition is required for rule %s", rule.RuleID)
+		v.problems = problems
+	}
+	v.validateRuleSet(ruleSet)
+
+	mode := s.cfg.RuleSyscallCheck
+	if strict {
+		mode = SyscallCheckStrict
+	}
+
+	var warnings []models.ValidationProblem
+	if mode != SyscallCheckOff && s.syscalls != nil {
+		callable, err := s.syscalls.CallableSyscallSet(context.TODO())
+		switch {
+		case err != nil && mode == SyscallCheckStrict:
+			return nil, fmt.Errorf("failed to check callable syscalls: %w", err)
+		case err != nil:
+			log.Printf("WARN: Skipping callable syscall check: %v", err)
+			warnings = append(warnings, models.ValidationProblem{Pointer: "", Message: "callable syscall check skipped: " + err.Error()})
+		case len(callable) == 0:
+			// 클러스터 분석 결과가 아직 없으면 모든 syscall이 unknown이 되므로 검사하지 않음
+			warnings = append(warnings, models.ValidationProblem{Pointer: "", Message: "callable syscall check skipped: " + SyscallSetKey + " is empty"})
+		default:
+			unknown := checkRuleSyscalls(ruleSet, callable)
+			if mode == SyscallCheckStrict {
+				v.problems = append(v.problems, unknown...)
+			} else {
+				warnings = append(warnings, unknown...)
+			}
 		}
 	}
-	return nil
+
+	if len(v.problems) > 0 {
+		return warnings, &RuleValidationError{Problems: v.problems, Warnings: warnings}
+	}
+	return warnings, nil
 }
@@ -0,0 +1,282 @@
+package services
+
+import (
+	"admin_server/backend/internal/config"
+	"admin_server/backend/internal/models"
+	"context"
+	"errors"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+const (
+	testNamespace = "default"
+	testConfigMap = "rule-policy-config"
+)
+
+func testRuleSet(version string, ruleIDs ...string) *models.RuleSet {
+	ruleSet := &models.RuleSet{RulesetVersion: version, Description: "test rules"}
+	for _, id := range ruleIDs {
+		ruleSet.Rules = append(ruleSet.Rules, models.Rule{
+			RuleID:      id,
+			Description: "exec of " + id,
+			Severity:    "high",
+			Conditions:  []models.Condition{{Field: "comm", Operator: OpEquals, Value: id}},
+		})
+	}
+	return ruleSet
+}
+
+func testRuleConfigMap(t *testing.T, resourceVersion string, ruleSet *models.RuleSet) *corev1.ConfigMap {
+	t.Helper()
+	data, err := yaml.Marshal(ruleSet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: testConfigMap, Namespace: testNamespace, ResourceVersion: resourceVersion},
+		Data:       map[string]string{"rule.yaml": string(data)},
+	}
+}
+
+// newTestRuleService returns the default RuleService on a fake clientset holding the
+// rule ConfigMap at resourceVersion "7"
+func newTestRuleService(t *testing.T) (*RuleService, *fake.Clientset) {
+	t.Helper()
+	clientset := fake.NewClientset(testRuleConfigMap(t, "7", testRuleSet("1.0.0", "R1")))
+	cfg := &config.Config{
+		Namespace:        testNamespace,
+		ConfigMapName:    testConfigMap,
+		ConfigMapKey:     "rule.yaml",
+		RuleHistoryStore: "memory",
+		RuleEditRetries:  1,
+	}
+	service, err := NewRuleService(cfg, clientset, nil, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return service, clientset
+}
+
+func storedRules(t *testing.T, clientset *fake.Clientset) (*models.RuleSet, *corev1.ConfigMap) {
+	t.Helper()
+	configMap, err := clientset.CoreV1().ConfigMaps(testNamespace).Get(context.Background(), testConfigMap, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ruleSet, err := parseRuleConfigMap(configMap, "rule.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ruleSet, configMap
+}
+
+func TestUpdateRulesRequiresIfMatch(t *testing.T) {
+	service, clientset := newTestRuleService(t)
+
+	_, err := service.UpdateRules(testRuleSet("1.1.0", "R1", "R2"), "", "alice")
+	if !errors.Is(err, ErrPreconditionRequired) {
+		t.Fatalf("err = %v, want ErrPreconditionRequired", err)
+	}
+	if stored, _ := storedRules(t, clientset); stored.RulesetVersion != "1.0.0" {
+		t.Fatalf("ConfigMap was written without If-Match (ruleset_version %s)", stored.RulesetVersion)
+	}
+}
+
+func TestUpdateRulesRejectsStaleResourceVersion(t *testing.T) {
+	service, clientset := newTestRuleService(t)
+
+	_, err := service.UpdateRules(testRuleSet("1.1.0", "R1", "R2"), "6", "alice")
+	var conflict *RuleConflictError
+	if !errors.As(err, &conflict) {
+		t.Fatalf("err = %v, want *RuleConflictError", err)
+	}
+	if conflict.ResourceVersion != "7" || conflict.Current.RulesetVersion != "1.0.0" {
+		t.Fatalf("conflict reports resourceVersion %s with ruleset_version %s, want 7 and 1.0.0", conflict.ResourceVersion, conflict.Current.RulesetVersion)
+	}
+	if len(conflict.Diff.Added) != 1 || conflict.Diff.Added[0].RuleID != "R2" || conflict.Diff.RulesetVersionTo != "1.1.0" {
+		t.Fatalf("diff = %+v, want R2 added and ruleset_version 1.1.0", conflict.Diff)
+	}
+	if stored, _ := storedRules(t, clientset); stored.RulesetVersion != "1.0.0" {
+		t.Fatalf("stale write reached the ConfigMap (ruleset_version %s)", stored.RulesetVersion)
+	}
+}
+
+func TestUpdateRulesMapsAPIConflict(t *testing.T) {
+	service, clientset := newTestRuleService(t)
+
+	// 읽은 뒤 쓰기 전에 다른 클라이언트가 ConfigMap을 바꾼 상황: API 서버는 Conflict를 반환
+	concurrent := testRuleConfigMap(t, "8", testRuleSet("1.0.1", "R1", "R3"))
+	clientset.PrependReactor("update", "configmaps", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if err := clientset.Tracker().Update(corev1.SchemeGroupVersion.WithResource("configmaps"), concurrent, testNamespace); err != nil {
+			t.Fatal(err)
+		}
+		return true, nil, apierrors.NewConflict(schema.GroupResource{Resource: "configmaps"}, testConfigMap, errors.New("the object has been modified"))
+	})
+
+	_, err := service.UpdateRules(testRuleSet("1.1.0", "R1", "R2"), "7", "alice")
+	var conflict *RuleConflictError
+	if !errors.As(err, &conflict) {
+		t.Fatalf("err = %v, want *RuleConflictError", err)
+	}
+	if conflict.ResourceVersion != "8" || conflict.Current.RulesetVersion != "1.0.1" {
+		t.Fatalf("conflict reports resourceVersion %s with ruleset_version %s, want the concurrent write (8, 1.0.1)", conflict.ResourceVersion, conflict.Current.RulesetVersion)
+	}
+	if len(conflict.Diff.Removed) != 1 || conflict.Diff.Removed[0].RuleID != "R3" {
+		t.Fatalf("diff = %+v, want R3 (added concurrently) removed", conflict.Diff)
+	}
+	if history, err := service.GetRuleHistory(); err != nil || len(history.Revisions) != 0 {
+		t.Fatalf("history = %+v, %v; a rejected write must not be recorded", history, err)
+	}
+}
+
+func TestUpdateRulesWritesWithCurrentETag(t *testing.T) {
+	service, clientset := newTestRuleService(t)
+
+	// 캐시 동기화 전에는 API 서버에서 읽음
+	_, etag, err := service.GetRules()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if etag != "7" {
+		t.Fatalf("GetRules ETag = %s, want the live resourceVersion 7", etag)
+	}
+	if _, err := service.UpdateRules(testRuleSet("1.1.0", "R1", "R2"), etag, "alice"); err != nil {
+		t.Fatal(err)
+	}
+
+	stored, configMap := storedRules(t, clientset)
+	if stored.RulesetVersion != "1.1.0" || len(stored.Rules) != 2 {
+		t.Fatalf("stored ruleset_version %s with %d rules, want 1.1.0 with 2", stored.RulesetVersion, len(stored.Rules))
+	}
+	if configMap.Annotations[annotationUpdatedBy] != "alice" {
+		t.Fatalf("updated-by annotation = %q, want alice", configMap.Annotations[annotationUpdatedBy])
+	}
+	history, err := service.GetRuleHistory()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history.Revisions) != 1 || history.Revisions[0].ResourceVersion != "7" || history.Revisions[0].RulesetVersion != "1.0.0" {
+		t.Fatalf("history = %+v, want the replaced 1.0.0 at resourceVersion 7", history.Revisions)
+	}
+
+	// If-Match: * skips the comparison
+	if _, err := service.UpdateRules(testRuleSet("1.2.0", "R1"), AnyResourceVersion, "bob"); err != nil {
+		t.Fatalf("If-Match * : %v", err)
+	}
+}
+
+func TestUpdateRulesWithStaleCachedETagConflicts(t *testing.T) {
+	service, clientset := newTestRuleService(t)
+	// 뒤처진 informer 캐시: 오래된 ETag는 409가 될 뿐 쓰기가 유실되지 않음
+	service.watcher.mu.Lock()
+	service.watcher.ready, service.watcher.resourceVersion, service.watcher.ruleSet = true, "5", testRuleSet("0.9.0", "R0")
+	service.watcher.mu.Unlock()
+
+	_, etag, err := service.GetRules()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if etag != "5" {
+		t.Fatalf("GetRules ETag = %s, want the cached resourceVersion 5", etag)
+	}
+	_, err = service.UpdateRules(testRuleSet("1.1.0", "R1", "R2"), etag, "alice")
+	var conflict *RuleConflictError
+	if !errors.As(err, &conflict) || conflict.ResourceVersion != "7" {
+		t.Fatalf("err = %v, want *RuleConflictError at resourceVersion 7", err)
+	}
+	if stored, _ := storedRules(t, clientset); stored.RulesetVersion != "1.0.0" {
+		t.Fatalf("stale ETag reached the ConfigMap (ruleset_version %s)", stored.RulesetVersion)
+	}
+}
+
+func TestValidateRulesRejectsEmptyGroups(t *testing.T) {
+	service, _ := newTestRuleService(t)
+	var ruleSet models.RuleSet
+	err := yaml.Unmarshal([]byte(`ruleset_version: 1.0.0
+rules:
+  - rule_id: R1
+    description: empty groups
+    severity: high
+    conditions:
+      - all: []
+      - not:
+          any: []
+`), &ruleSet)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = service.ValidateRules(&ruleSet, false)
+	var invalid *RuleValidationError
+	if !errors.As(err, &invalid) {
+		t.Fatalf("err = %v, want *RuleValidationError", err)
+	}
+	pointers := map[string]bool{}
+	for _, problem := range invalid.Problems {
+		pointers[problem.Pointer] = true
+	}
+	if !pointers["/rules/0/conditions/0/all"] || !pointers["/rules/0/conditions/1/not/any"] {
+		t.Fatalf("problems = %+v, want both empty groups reported", invalid.Problems)
+	}
+}
+
+func TestImportRulesDryRunReportsTemplateProblems(t *testing.T) {
+	service, clientset := newTestRuleService(t)
+	data := []byte(`{"rules":[{"rule_id":"R9","description":"from a missing template","severity":"high","template":"missing","params":{"binary":"nc"}}]}`)
+
+	_, err := service.ImportRules(data, "json", ImportModeMerge, true, "", "alice", false)
+	var invalid *RuleValidationError
+	if !errors.As(err, &invalid) {
+		t.Fatalf("err = %v, want *RuleValidationError", err)
+	}
+	if len(invalid.Problems) != 1 || invalid.Problems[0].Pointer != "/rules/1/template" {
+		t.Fatalf("problems = %+v, want the unknown template of the imported rule", invalid.Problems)
+	}
+	if stored, _ := storedRules(t, clientset); len(stored.Rules) != 1 {
+		t.Fatalf("dry run wrote %d rules", len(stored.Rules))
+	}
+}
+
+func TestStageRulesOverwritesUnreadableCandidate(t *testing.T) {
+	for name, data := range map[string]map[string]string{
+		"missing key":  {"other.yaml": "x"},
+		"invalid yaml": {"rule.yaml": "rules: [unclosed"},
+		"no data":      nil,
+	} {
+		t.Run(name, func(t *testing.T) {
+			service, clientset := newTestRuleService(t)
+			broken := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: service.ruleset.CandidateConfigMap, Namespace: testNamespace, ResourceVersion: "3"},
+				Data:       data,
+			}
+			if _, err := clientset.CoreV1().ConfigMaps(testNamespace).Create(context.Background(), broken, metav1.CreateOptions{}); err != nil {
+				t.Fatal(err)
+			}
+
+			// 깨진 후보도 If-Match 비교는 그대로 적용됨
+			var conflict *RuleConflictError
+			if _, err := service.StageRules(testRuleSet("1.1.0", "R1"), "2", "alice"); !errors.As(err, &conflict) {
+				t.Fatalf("stale If-Match: err = %v, want *RuleConflictError", err)
+			}
+			if _, err := service.StageRules(testRuleSet("1.1.0", "R1"), "", "alice"); err != nil {
+				t.Fatalf("restaging over a broken candidate: %v", err)
+			}
+			candidate, _, err := service.GetCandidateRules()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if candidate.RulesetVersion != "1.1.0" {
+				t.Fatalf("candidate ruleset_version %s, want 1.1.0", candidate.RulesetVersion)
+			}
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package services
+
+import (
+	"admin_server/backend/internal/evaluator"
+	"admin_server/backend/internal/models"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+)
+
+// Simulation sources
+const (
+	SimulationSourceUpload = "upload"
+	SimulationSourceAlerts = "alerts"
+)
+
+// MaxSimulationSamples caps the sample matches reported per rule
+const MaxSimulationSamples = 50
+
+// ErrInvalidSimulation is returned for unreadable or oversized simulation input
+var ErrInvalidSimulation = errors.New("invalid simulation request")
+
+// SimulationEvent is one event fed to SimulateRules
+type SimulationEvent struct {
+	Event   evaluator.Event
+	AlertID string // set for events taken from stored alerts
+}
+
+// ReadSimulationEvents parses a JSONL upload, at most RULE_SIMULATE_MAX_EVENTS events
+func (s *RuleService) ReadSimulationEvents(r io.Reader) ([]SimulationEvent, error) {
+	events, err := evaluator.ReadEvents(r, s.cfg.RuleSimulateMaxEvents)
+	if err != nil {
+		return nil, fmt.Errorf("%w: events: %w", ErrInvalidSimulation, err)
+	}
+	batch := make([]SimulationEvent, len(events))
+	for i, event := range events {
+		batch[i] = SimulationEvent{Event: event}
+	}
+	return batch, nil
+}
+
+// AlertSimulationEvents turns stored alerts into events: the syscall_log, plus the
+// alert's pod_name and namespace where the log does not carry them
+func AlertSimulationEvents(alerts []models.Alert) []SimulationEvent {
+	batch := make([]SimulationEvent, 0, len(alerts))
+	for _, alert := range alerts {
+		event := make(evaluator.Event, len(alert.SyscallLog)+2)
+		for k, v := range alert.SyscallLog {
+			event[k] = v
+		}
+		if _, ok := event["pod_name"]; !ok && alert.PodName != "" {
+			event["pod_name"] = alert.PodName
+		}
+		if _, ok := event["namespace"]; !ok && alert.Namespace != "" {
+			event["namespace"] = alert.Namespace
+		}
+		batch = append(batch, SimulationEvent{Event: event, AlertID: alert.AlertID})
+	}
+	return batch
+}
+
+// SimulateRules evaluates ruleSet (the live rules when nil) against events and reports
+// per-rule match counts with up to samples matching events each. The RuleSet is
+// validated like PUT /rules first, so evaluation errors are limited to the unexpected;
+// such an error stops that rule and is reported on it.
+func (s *RuleService) SimulateRules(ruleSet *models.RuleSet, events []SimulationEvent, source string, samples int) (*models.SimulateRulesResponse, error) {
+	if ruleSet == nil {
+		live, _, err := s.GetRules()
+		if err != nil {
+			return nil, err
+		}
+		ruleSet = live
+	}
+	if limit := s.cfg.RuleSimulateMaxEvents; limit > 0 && len(events) > limit {
+		return nil, fmt.Errorf("%w: %d events exceed the limit of %d", ErrInvalidSimulation, len(events), limit)
+	}
+	samples = min(max(samples, 0), MaxSimulationSamples)
+
+	warnings, err := s.ValidateRules(ruleSet, false)
+	if err != nil {
+		return nil, err
+	}
+	if ruleSet, err = s.expandRuleSet(ruleSet); err != nil {
+		return nil, err
+	}
+
+	response := &models.SimulateRulesResponse{
+		RulesetVersion: ruleSet.RulesetVersion,
+		Source:         source,
+		Events:         len(events),
+		Rules:          make([]models.RuleSimulation, 0, len(ruleSet.Rules)),
+		Warnings:       warnings,
+	}
+	if response.Warnings == nil {
+		response.Warnings = []models.ValidationProblem{}
+	}
+
+	matched := make([]bool, len(events))
+	for _, rule := range ruleSet.Rules {
+		result := models.RuleSimulation{
+			RuleID:  rule.RuleID,
+			Enabled: rule.IsEnabled(),
+			Samples: make([]models.SimulationMatch, 0),
+		}
+		for i, event := range events {
+			ok, err := evaluator.Match(rule.Conditions, event.Event)
+			if err != nil {
+				result.Error = fmt.Sprintf("event %d: %v", i, err)
+				break
+			}
+			if !ok {
+				continue
+			}
+			result.Matches++
+			if result.Enabled {
+				matched[i] = true
+			}
+			if len(result.Samples) < samples {
+				result.Samples = append(result.Samples, models.SimulationMatch{Index: i, AlertID: event.AlertID, Event: event.Event})
+			}
+		}
+		response.Rules = append(response.Rules, result)
+	}
+	for _, m := range matched {
+		if m {
+			response.Matched++
+		}
+	}
+
+	log.Printf("Simulated ruleset_version %s against %d %s events: %d matched", ruleSet.RulesetVersion, len(events), source, response.Matched)
+	return response, nil
+}
@@ -0,0 +1,76 @@
+package services
+
+import (
+	"admin_server/backend/internal/evaluator"
+	"admin_server/backend/internal/models"
+	"fmt"
+)
+
+// Syscall cross-check modes (RULE_SYSCALL_CHECK)
+const (
+	SyscallCheckWarn   = "warn"
+	SyscallCheckStrict = "strict"
+	SyscallCheckOff    = "off"
+)
+
+// checkRuleSyscalls reports every condition on a syscall-name field whose value
+// names a syscall outside callable. Pattern operators are reported when they
+// match no callable syscall at all.
+func checkRuleSyscalls(ruleSet *models.RuleSet, callable map[string]struct{}) []models.ValidationProblem {
+	var problems []models.ValidationProblem
+	for i, rule := range ruleSet.Rules {
+		walkConditions(fmt.Sprintf("/rules/%d/conditions", i), rule.Conditions, func(pointer string, cond models.Condition) {
+			spec, ok := LookupField(cond.Field)
+			if !ok || !spec.Syscall {
+				return
+			}
+			problems = append(problems, checkSyscallCondition(pointer+"/value", cond, callable)...)
+		})
+	}
+	return problems
+}
+
+func checkSyscallCondition(pointer string, cond models.Condition, callable map[string]struct{}) []models.ValidationProblem {
+	var problems []models.ValidationProblem
+	unknown := func(pointer, name string) {
+		if _, ok := callable[name]; !ok {
+			problems = append(problems, models.ValidationProblem{
+				Pointer: pointer,
+				Message: fmt.Sprintf("syscall %q is not in %s", name, SyscallSetKey),
+			})
+		}
+	}
+
+	switch cond.Operator {
+	case OpEquals, OpNotEquals:
+		if name, ok := cond.Value.(string); ok {
+			unknown(pointer, name)
+		}
+	case OpIn, OpNotIn:
+		items, _ := cond.Value.([]interface{})
+		for k, item := range items {
+			if name, ok := item.(string); ok {
+				unknown(fmt.Sprintf("%s/%d", pointer, k), name)
+			}
+		}
+	case OpPrefix, OpSuffix, OpContains, OpRegex:
+		pattern, ok := cond.Value.(string)
+		if !ok {
+			break
+		}
+		for name := range callable {
+			matched, err := evaluator.MatchString(cond.Operator, name, pattern)
+			if err != nil {
+				return nil // invalid regex, already reported by the schema check
+			}
+			if matched {
+				return nil
+			}
+		}
+		problems = append(problems, models.ValidationProblem{
+			Pointer: pointer,
+			Message: fmt.Sprintf("%s %q matches no syscall in %s", cond.Operator, pattern, SyscallSetKey),
+		})
+	}
+	return problems
+}
@@ -0,0 +1,232 @@
+package services
+
+import (
+	"admin_server/backend/internal/config"
+	"admin_server/backend/internal/models"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// ruleTemplateKey is the data key of the template ConfigMap
+const ruleTemplateKey = "templates.yaml"
+
+var (
+	// ErrTemplateNotFound is returned for a template_id that is not stored
+	ErrTemplateNotFound = errors.New("rule template not found")
+	// ErrTemplateExists is returned when creating a template_id that is already stored
+	ErrTemplateExists = errors.New("rule template already exists")
+	// ErrTemplateConflict is returned when the template ConfigMap changed after the caller read it
+	ErrTemplateConflict = errors.New("rule template ConfigMap was modified concurrently")
+	// ErrInvalidTemplate is returned for a template body that does not match its path
+	ErrInvalidTemplate = errors.New("invalid rule template")
+	// ErrTemplateInUse is returned when deleting a template that rules are still built from
+	ErrTemplateInUse = errors.New("rule template is in use")
+)
+
+// RuleTemplateService stores rule templates in their own ConfigMap
+// (RULE_TEMPLATE_CONFIG_MAP_NAME in NAMESPACE). A missing ConfigMap reads as no
+// templates and is created on the first write.
+type RuleTemplateService struct {
+	cfg       *config.Config
+	clientset kubernetes.Interface
+}
+
+func NewRuleTemplateService(cfg *config.Config, clientset kubernetes.Interface) *RuleTemplateService {
+	return &RuleTemplateService{cfg: cfg, clientset: clientset}
+}
+
+// ListTemplates returns every template and the ConfigMap resourceVersion ("" while
+// the ConfigMap does not exist)
+func (s *RuleTemplateService) ListTemplates() (*models.RuleTemplateSet, string, error) {
+	configMap, templates, err := s.getTemplateConfigMap()
+	if err != nil {
+		return nil, "", err
+	}
+	if configMap == nil {
+		return templates, "", nil
+	}
+	return templates, configMap.ResourceVersion, nil
+}
+
+// GetTemplate returns one template and the ConfigMap resourceVersion
+func (s *RuleTemplateService) GetTemplate(templateID string) (*models.RuleTemplate, string, error) {
+	templates, resourceVersion, err := s.ListTemplates()
+	if err != nil {
+		return nil, "", err
+	}
+	i := findTemplate(templates, templateID)
+	if i < 0 {
+		return nil, "", fmt.Errorf("%w: %s", ErrTemplateNotFound, templateID)
+	}
+	return &templates.Templates[i], resourceVersion, nil
+}
+
+// CreateTemplate adds a template under templateID
+func (s *RuleTemplateService) CreateTemplate(templateID string, template *models.RuleTemplate, expectedVersion, author string) (*models.RuleTemplateUpdateResponse, error) {
+	if err := checkTemplate(templateID, template); err != nil {
+		return nil, err
+	}
+	return s.editTemplates(expectedVersion, author, func(templates *models.RuleTemplateSet) error {
+		if findTemplate(templates, templateID) >= 0 {
+			return fmt.Errorf("%w: %s", ErrTemplateExists, templateID)
+		}
+		templates.Templates = append(templates.Templates, *template)
+		return nil
+	})
+}
+
+// ReplaceTemplate overwrites the template stored under templateID. Rules built from it
+// pick up the change the next time their ruleset is written.
+func (s *RuleTemplateService) ReplaceTemplate(templateID string, template *models.RuleTemplate, expectedVersion, author string) (*models.RuleTemplateUpdateResponse, error) {
+	if err := checkTemplate(templateID, template); err != nil {
+		return nil, err
+	}
+	return s.editTemplates(expectedVersion, author, func(templates *models.RuleTemplateSet) error {
+		i := findTemplate(templates, templateID)
+		if i < 0 {
+			return fmt.Errorf("%w: %s", ErrTemplateNotFound, templateID)
+		}
+		templates.Templates[i] = *template
+		return nil
+	})
+}
+
+// DeleteTemplate removes the template stored under templateID. Callers check
+// RuleService.TemplateUsage first; a rule naming a deleted template fails validation.
+func (s *RuleTemplateService) DeleteTemplate(templateID, expectedVersion, author string) (*models.RuleTemplateUpdateResponse, error) {
+	return s.editTemplates(expectedVersion, author, func(templates *models.RuleTemplateSet) error {
+		i := findTemplate(templates, templateID)
+		if i < 0 {
+			return fmt.Errorf("%w: %s", ErrTemplateNotFound, templateID)
+		}
+		templates.Templates = append(templates.Templates[:i], templates.Templates[i+1:]...)
+		return nil
+	})
+}
+
+// editTemplates applies edit to the stored templates and writes them back, creating the
+// ConfigMap on first use. Without expectedVersion (If-Match) a concurrent write is retried
+// up to RULE_EDIT_RETRIES times; with it the write fails with ErrTemplateConflict.
+func (s *RuleTemplateService) editTemplates(expectedVersion, author string, edit func(*models.RuleTemplateSet) error) (*models.RuleTemplateUpdateResponse, error) {
+	if author == "" {
+		author = "anonymous"
+	}
+	pinned := expectedVersion != "" && expectedVersion != AnyResourceVersion
+	configMaps := s.clientset.CoreV1().ConfigMaps(s.cfg.Namespace)
+
+	attempts := max(s.cfg.RuleEditRetries, 1)
+	for attempt := 1; ; attempt++ {
+		configMap, templates, err := s.getTemplateConfigMap()
+		if err != nil {
+			return nil, err
+		}
+		current := ""
+		if configMap != nil {
+			current = configMap.ResourceVersion
+		}
+		if pinned && current != expectedVersion {
+			return nil, fmt.Errorf("%w (current resourceVersion %s)", ErrTemplateConflict, current)
+		}
+		if err := edit(templates); err != nil {
+			return nil, err
+		}
+		data, err := yaml.Marshal(templates)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal rule templates to YAML: %w", err)
+		}
+
+		var updated *corev1.ConfigMap
+		if configMap == nil {
+			updated, err = configMaps.Create(context.TODO(), &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        s.cfg.RuleTemplateConfigMapName,
+					Namespace:   s.cfg.Namespace,
+					Annotations: map[string]string{annotationUpdatedBy: author},
+				},
+				Data: map[string]string{ruleTemplateKey: string(data)},
+			}, metav1.CreateOptions{FieldManager: ruleFieldManager})
+		} else {
+			if configMap.Data == nil {
+				configMap.Data = map[string]string{}
+			}
+			configMap.Data[ruleTemplateKey] = string(data)
+			if configMap.Annotations == nil {
+				configMap.Annotations = map[string]string{}
+			}
+			configMap.Annotations[annotationUpdatedBy] = author
+			updated, err = configMaps.Update(context.TODO(), configMap, metav1.UpdateOptions{FieldManager: ruleFieldManager})
+		}
+		if apierrors.IsConflict(err) || apierrors.IsAlreadyExists(err) {
+			if pinned || attempt >= attempts {
+				return nil, fmt.Errorf("%w: %v", ErrTemplateConflict, err)
+			}
+			log.Printf("Rule template ConfigMap changed during edit, retrying (%d/%d)", attempt, attempts)
+			continue
+		}
+		if err != nil {
+			log.Printf("ERROR: Failed to write rule template ConfigMap via K8s API: %v", err)
+			return nil, fmt.Errorf("failed to write rule template ConfigMap via K8s API: %w", err)
+		}
+
+		return &models.RuleTemplateUpdateResponse{
+			Status:          "success",
+			Message:         "Rule template ConfigMap updated successfully.",
+			ResourceVersion: updated.ResourceVersion,
+		}, nil
+	}
+}
+
+// getTemplateConfigMap returns the template ConfigMap (nil when it does not exist yet)
+// and the templates it holds
+func (s *RuleTemplateService) getTemplateConfigMap() (*corev1.ConfigMap, *models.RuleTemplateSet, error) {
+	templates := &models.RuleTemplateSet{Templates: []models.RuleTemplate{}}
+	configMap, err := s.clientset.CoreV1().ConfigMaps(s.cfg.Namespace).Get(context.TODO(), s.cfg.RuleTemplateConfigMapName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, templates, nil
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get rule template ConfigMap via K8s API: %w", err)
+	}
+	if err := yaml.Unmarshal([]byte(configMap.Data[ruleTemplateKey]), templates); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse %s of ConfigMap %s: %w", ruleTemplateKey, configMap.Name, err)
+	}
+	if templates.Templates == nil {
+		templates.Templates = []models.RuleTemplate{}
+	}
+	return configMap, templates, nil
+}
+
+// checkTemplate fills in template_id from the path and validates the template
+func checkTemplate(templateID string, template *models.RuleTemplate) error {
+	if template.TemplateID == "" {
+		template.TemplateID = templateID
+	}
+	if template.TemplateID != templateID {
+		return fmt.Errorf("%w: template_id %q in body does not match %q in path", ErrInvalidTemplate, template.TemplateID, templateID)
+	}
+	if strings.Contains(templateID, "/") {
+		return fmt.Errorf("%w: template_id %q must not contain '/'", ErrInvalidTemplate, templateID)
+	}
+	if problems := validateTemplate(template); len(problems) > 0 {
+		return &RuleValidationError{Problems: problems}
+	}
+	return nil
+}
+
+func findTemplate(templates *models.RuleTemplateSet, templateID string) int {
+	for i, template := range templates.Templates {
+		if template.TemplateID == templateID {
+			return i
+		}
+	}
+	return -1
+}
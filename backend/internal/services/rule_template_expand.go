@@ -0,0 +1,319 @@
+package services
+
+import (
+	"admin_server/backend/internal/models"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+)
+
+// placeholderPattern matches a ${name} template placeholder
+var placeholderPattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+var parameterNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// usesTemplates reports whether any rule of ruleSet names a template or carries params
+func usesTemplates(ruleSet *models.RuleSet) bool {
+	for _, rule := range ruleSet.Rules {
+		if rule.Template != "" || len(rule.Params) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// applyTemplates replaces, in place, the conditions of every rule naming a template with
+// the template's conditions expanded from the rule's params. Rules that cannot be expanded
+// keep their conditions and are reported as problems located in ruleSet.
+func (s *RuleService) applyTemplates(ruleSet *models.RuleSet) ([]models.ValidationProblem, error) {
+	if !usesTemplates(ruleSet) {
+		return nil, nil
+	}
+	templates := map[string]*models.RuleTemplate{}
+	if s.templates != nil {
+		templateSet, _, err := s.templates.ListTemplates()
+		if err != nil {
+			return nil, err
+		}
+		for i := range templateSet.Templates {
+			templates[templateSet.Templates[i].TemplateID] = &templateSet.Templates[i]
+		}
+	}
+
+	var problems []models.ValidationProblem
+	for i := range ruleSet.Rules {
+		rule := &ruleSet.Rules[i]
+		pointer := fmt.Sprintf("/rules/%d", i)
+		if rule.Template == "" {
+			if len(rule.Params) > 0 {
+				problems = append(problems, models.ValidationProblem{Pointer: pointer + "/params", Message: "params require a template"})
+			}
+			continue
+		}
+		template, ok := templates[rule.Template]
+		if !ok {
+			problems = append(problems, models.ValidationProblem{Pointer: pointer + "/template", Message: fmt.Sprintf("unknown template %q", rule.Template)})
+			continue
+		}
+		conditions, ruleProblems := expandTemplate(pointer, template, rule.Params)
+		problems = append(problems, ruleProblems...)
+		if ruleProblems == nil {
+			rule.Conditions = conditions
+		}
+	}
+	return problems, nil
+}
+
+// expandRuleSet returns a copy of ruleSet with its template rules expanded (or ruleSet
+// itself when it uses no templates). Expansion problems are returned as a *RuleValidationError.
+func (s *RuleService) expandRuleSet(ruleSet *models.RuleSet) (*models.RuleSet, error) {
+	if !usesTemplates(ruleSet) {
+		return ruleSet, nil
+	}
+	expanded := cloneRuleSet(ruleSet)
+	problems, err := s.applyTemplates(expanded)
+	if err != nil {
+		return nil, err
+	}
+	if len(problems) > 0 {
+		return nil, &RuleValidationError{Problems: problems}
+	}
+	return expanded, nil
+}
+
+// TemplateUsage lists the rules, as "ruleset/rule_id", built from templateID in any
+// registered ruleset. Rulesets that cannot be read are skipped.
+func (s *RuleService) TemplateUsage(templateID string) []string {
+	usedBy := make([]string, 0)
+	for _, name := range s.registry.names {
+		ruleSet, _, err := s.registry.services[name].GetRules()
+		if err != nil {
+			continue
+		}
+		for _, rule := range ruleSet.Rules {
+			if rule.Template == templateID {
+				usedBy = append(usedBy, name+"/"+rule.RuleID)
+			}
+		}
+	}
+	return usedBy
+}
+
+// expandTemplate substitutes params, over the parameter defaults, into the template's
+// conditions. pointer locates the rule for problems.
+func expandTemplate(pointer string, template *models.RuleTemplate, params models.RuleParams) ([]models.Condition, []models.ValidationProblem) {
+	var problems []models.ValidationProblem
+	values := make(map[string]interface{}, len(template.Parameters))
+	for _, param := range template.Parameters {
+		if value, ok := params[param.Name]; ok {
+			values[param.Name] = value
+		} else if param.Default != nil {
+			values[param.Name] = param.Default
+		} else {
+			problems = append(problems, models.ValidationProblem{
+				Pointer: pointer + "/params/" + param.Name,
+				Message: fmt.Sprintf("template %q requires parameter %q", template.TemplateID, param.Name),
+			})
+		}
+	}
+	names := make([]string, 0, len(params))
+	for name := range params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if !slices.ContainsFunc(template.Parameters, func(p models.TemplateParameter) bool { return p.Name == name }) {
+			problems = append(problems, models.ValidationProblem{
+				Pointer: pointer + "/params/" + name,
+				Message: fmt.Sprintf("template %q has no parameter %q", template.TemplateID, name),
+			})
+		}
+	}
+	if problems != nil {
+		return nil, problems
+	}
+
+	conditions, err := substituteConditions(template.Conditions, values)
+	if err != nil {
+		return nil, []models.ValidationProblem{{Pointer: pointer + "/params", Message: err.Error()}}
+	}
+	return conditions, nil
+}
+
+func substituteConditions(conditions []models.Condition, values map[string]interface{}) ([]models.Condition, error) {
+	if conditions == nil {
+		return nil, nil
+	}
+	expanded := make([]models.Condition, len(conditions))
+	for i, cond := range conditions {
+		var err error
+		if expanded[i].Field, err = substituteString(cond.Field, values); err != nil {
+			return nil, err
+		}
+		expanded[i].Operator = cond.Operator
+		if expanded[i].Value, err = substituteValue(cond.Value, values); err != nil {
+			return nil, err
+		}
+		if expanded[i].All, err = substituteConditions(cond.All, values); err != nil {
+			return nil, err
+		}
+		if expanded[i].Any, err = substituteConditions(cond.Any, values); err != nil {
+			return nil, err
+		}
+		if cond.Not != nil {
+			not, err := substituteConditions([]models.Condition{*cond.Not}, values)
+			if err != nil {
+				return nil, err
+			}
+			expanded[i].Not = &not[0]
+		}
+	}
+	return expanded, nil
+}
+
+// substituteValue replaces a value that is exactly "${name}" with the parameter value,
+// whatever its type; a list item that is exactly "${name}" takes a list parameter's
+// items. Placeholders inside longer strings are replaced with the parameter's text.
+func substituteValue(value interface{}, values map[string]interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case string:
+		if name, ok := wholePlaceholder(v); ok {
+			return copyValue(values[name]), nil
+		}
+		return substituteString(v, values)
+	case []interface{}:
+		items := make([]interface{}, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				if name, ok := wholePlaceholder(s); ok {
+					if list, ok := values[name].([]interface{}); ok {
+						items = append(items, copyValue(list).([]interface{})...)
+						continue
+					}
+				}
+			}
+			expanded, err := substituteValue(item, values)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, expanded)
+		}
+		return items, nil
+	}
+	return value, nil
+}
+
+func substituteString(s string, values map[string]interface{}) (string, error) {
+	var err error
+	expanded := placeholderPattern.ReplaceAllStringFunc(s, func(placeholder string) string {
+		name := placeholderPattern.FindStringSubmatch(placeholder)[1]
+		switch value := values[name].(type) {
+		case string:
+			return value
+		case float64: // JSON 숫자
+			return strconv.FormatFloat(value, 'f', -1, 64)
+		case []interface{}, map[string]interface{}, nil:
+			err = fmt.Errorf("parameter %q must be a string or number to be used inside %q", name, s)
+			return placeholder
+		default:
+			return fmt.Sprint(value)
+		}
+	})
+	return expanded, err
+}
+
+func wholePlaceholder(s string) (string, bool) {
+	match := placeholderPattern.FindStringSubmatch(s)
+	if match == nil || match[0] != s {
+		return "", false
+	}
+	return match[1], true
+}
+
+// copyValue deep-copies the lists and maps of a decoded JSON/YAML value, so expanded
+// rules and cloned RuleSets do not share them
+func copyValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case []interface{}:
+		list := make([]interface{}, len(v))
+		for i, item := range v {
+			list[i] = copyValue(item)
+		}
+		return list
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			m[key] = copyValue(item)
+		}
+		return m
+	}
+	return value
+}
+
+// placeholders returns the parameter names used in a leaf condition's field and value
+func placeholders(cond models.Condition) []string {
+	var names []string
+	collect := func(s string) {
+		for _, match := range placeholderPattern.FindAllStringSubmatch(s, -1) {
+			names = append(names, match[1])
+		}
+	}
+	collect(cond.Field)
+	switch v := cond.Value.(type) {
+	case string:
+		collect(v)
+	case []interface{}:
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				collect(s)
+			}
+		}
+	}
+	return names
+}
+
+// validateTemplate checks a template's id, parameters and conditions. Conditions are
+// checked like rule conditions, except that a leaf with placeholders is only checked
+// for its operator; the expanded rule is validated in full when it is written.
+func validateTemplate(template *models.RuleTemplate) []models.ValidationProblem {
+	v := &ruleValidator{templated: true}
+	if template.TemplateID == "" {
+		v.addf("/template_id", "template_id is required")
+	}
+
+	declared := make(map[string]int, len(template.Parameters))
+	for i, param := range template.Parameters {
+		pointer := fmt.Sprintf("/parameters/%d", i)
+		switch _, dup := declared[param.Name]; {
+		case !parameterNamePattern.MatchString(param.Name):
+			v.addf(pointer+"/name", "parameter name %q must be a letter or _ followed by letters, digits or _", param.Name)
+		case dup:
+			v.addf(pointer+"/name", "duplicate parameter %q", param.Name)
+		}
+		declared[param.Name] = i
+	}
+
+	if len(template.Conditions) == 0 {
+		v.addf("/conditions", "at least one condition is required")
+	}
+	used := make(map[string]bool)
+	walkConditions("/conditions", template.Conditions, func(pointer string, cond models.Condition) {
+		for _, name := range placeholders(cond) {
+			used[name] = true
+			if _, ok := declared[name]; !ok {
+				v.addf(pointer, "placeholder ${%s} is not a declared parameter", name)
+			}
+		}
+	})
+	for i, cond := range template.Conditions {
+		v.validateCondition(fmt.Sprintf("/conditions/%d", i), cond, 1)
+	}
+	for i, param := range template.Parameters {
+		if !used[param.Name] {
+			v.addf(fmt.Sprintf("/parameters/%d", i), "parameter %q is not used by any condition", param.Name)
+		}
+	}
+	return v.problems
+}
@@ -0,0 +1,327 @@
+package services
+
+import (
+	"admin_server/backend/internal/models"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+)
+
+const (
+	// ruleFieldManager is the managedFields manager name used for writes from this server
+	ruleFieldManager = "admin-server"
+	// annotationUpdatedBy names the admin user behind the last write from this server
+	annotationUpdatedBy = "admin-server/updated-by"
+	// ruleEventHistory is the number of recent change events kept for Last-Event-ID resume
+	ruleEventHistory = 50
+)
+
+// errRuleCacheNotReady means the informer has not synced (or the ConfigMap is gone)
+var errRuleCacheNotReady = errors.New("rule cache is not ready")
+
+// RuleEventSubscription receives rule change events until closed. C is closed when
+// the subscriber is dropped for falling behind or after Close.
+type RuleEventSubscription struct {
+	C <-chan models.RuleChangeEvent
+
+	ch      chan models.RuleChangeEvent
+	watcher *ruleWatcher
+	once    sync.Once
+}
+
+// Close unregisters the subscription
+func (sub *RuleEventSubscription) Close() {
+	sub.watcher.remove(sub)
+}
+
+// ruleWatcher runs an informer on the live rule ConfigMap, caches the parsed RuleSet
+// and broadcasts change events. Like AlertBroadcaster, publishing never blocks.
+type ruleWatcher struct {
+	clientset  kubernetes.Interface
+	namespace  string
+	name       string
+	key        string
+	bufferSize int
+
+	mu              sync.RWMutex
+	ready           bool
+	ruleSet         *models.RuleSet
+	resourceVersion string
+	parseErr        error
+	recent          []models.RuleChangeEvent
+	subscribers     map[*RuleEventSubscription]struct{}
+}
+
+func newRuleWatcher(clientset kubernetes.Interface, namespace, name, key string, bufferSize int) *ruleWatcher {
+	if bufferSize <= 0 {
+		bufferSize = 1
+	}
+	return &ruleWatcher{
+		clientset:   clientset,
+		namespace:   namespace,
+		name:        name,
+		key:         key,
+		bufferSize:  bufferSize,
+		subscribers: make(map[*RuleEventSubscription]struct{}),
+	}
+}
+
+// Start runs the informer until ctx is cancelled. The cache serves reads once the
+// initial list has synced; until then callers fall back to the API server.
+func (w *ruleWatcher) Start(ctx context.Context) {
+	factory := informers.NewSharedInformerFactoryWithOptions(w.clientset, 0,
+		informers.WithNamespace(w.namespace),
+		informers.WithTweakListOptions(func(opts *metav1.ListOptions) {
+			opts.FieldSelector = fields.OneTermEqualSelector("metadata.name", w.name).String()
+		}),
+	)
+	informer := factory.Core().V1().ConfigMaps().Informer()
+	informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if cm, ok := obj.(*corev1.ConfigMap); ok {
+				w.apply(models.RuleEventAdded, cm)
+			}
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			oldCM, _ := oldObj.(*corev1.ConfigMap)
+			cm, ok := newObj.(*corev1.ConfigMap)
+			if !ok || (oldCM != nil && oldCM.ResourceVersion == cm.ResourceVersion) {
+				return
+			}
+			w.apply(models.RuleEventUpdated, cm)
+		},
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			if cm, ok := obj.(*corev1.ConfigMap); ok {
+				w.delete(cm)
+			}
+		},
+	})
+
+	factory.Start(ctx.Done())
+	go func() {
+		if cache.WaitForCacheSync(ctx.Done(), informer.HasSynced) {
+			w.mu.Lock()
+			w.ready = true
+			w.mu.Unlock()
+			log.Printf("Watching ConfigMap %s/%s for rule changes", w.namespace, w.name)
+		}
+	}()
+}
+
+// Get returns a copy of the cached RuleSet and its resourceVersion
+func (w *ruleWatcher) Get() (*models.RuleSet, string, error) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	if !w.ready || w.resourceVersion == "" {
+		return nil, "", errRuleCacheNotReady
+	}
+	if w.parseErr != nil {
+		return nil, "", w.parseErr
+	}
+	return cloneRuleSet(w.ruleSet), w.resourceVersion, nil
+}
+
+func (w *ruleWatcher) apply(eventType string, cm *corev1.ConfigMap) {
+	ruleSet, err := parseRuleConfigMap(cm, w.key)
+	manager, author := configMapAuthor(cm)
+	event := models.RuleChangeEvent{
+		Type:            eventType,
+		ConfigMap:       cm.Name,
+		ResourceVersion: cm.ResourceVersion,
+		FieldManager:    manager,
+		Author:          author,
+		Timestamp:       time.Now().UTC().Format(time.RFC3339),
+	}
+
+	w.mu.Lock()
+	previous := w.ruleSet
+	if err != nil {
+		log.Printf("WARN: Watched ConfigMap %s has invalid rules: %v", cm.Name, err)
+		event.Error = err.Error()
+		w.ruleSet, w.parseErr = nil, err
+	} else {
+		event.RulesetVersion = ruleSet.RulesetVersion
+		if previous != nil {
+			diff := DiffRuleSets(previous, ruleSet)
+			event.Diff = &diff
+		}
+		w.ruleSet, w.parseErr = ruleSet, nil
+	}
+	w.resourceVersion = cm.ResourceVersion
+	w.mu.Unlock()
+
+	log.Printf("Rule ConfigMap %s %s (resourceVersion %s, ruleset_version %s) by %s", cm.Name, eventType, cm.ResourceVersion, event.RulesetVersion, author)
+	w.publish(event)
+}
+
+func (w *ruleWatcher) delete(cm *corev1.ConfigMap) {
+	w.mu.Lock()
+	w.ruleSet, w.resourceVersion, w.parseErr = nil, "", nil
+	w.mu.Unlock()
+
+	log.Printf("WARN: Rule ConfigMap %s was deleted", cm.Name)
+	w.publish(models.RuleChangeEvent{
+		Type:            models.RuleEventDeleted,
+		ConfigMap:       cm.Name,
+		ResourceVersion: cm.ResourceVersion,
+		Timestamp:       time.Now().UTC().Format(time.RFC3339),
+	})
+}
+
+// Subscribe registers a subscriber and returns the recent events after lastEventID
+// (a resourceVersion) to replay first. An unknown or empty lastEventID replays nothing.
+func (w *ruleWatcher) Subscribe(lastEventID string) ([]models.RuleChangeEvent, *RuleEventSubscription) {
+	ch := make(chan models.RuleChangeEvent, w.bufferSize)
+	sub := &RuleEventSubscription{C: ch, ch: ch, watcher: w}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.subscribers[sub] = struct{}{}
+
+	var replay []models.RuleChangeEvent
+	if lastEventID != "" {
+		for i, event := range w.recent {
+			if event.ResourceVersion == lastEventID {
+				replay = append(replay, w.recent[i+1:]...)
+				break
+			}
+		}
+	}
+	return replay, sub
+}
+
+func (w *ruleWatcher) publish(event models.RuleChangeEvent) {
+	var slow []*RuleEventSubscription
+
+	w.mu.Lock()
+	w.recent = append(w.recent, event)
+	if len(w.recent) > ruleEventHistory {
+		w.recent = w.recent[len(w.recent)-ruleEventHistory:]
+	}
+	for sub := range w.subscribers {
+		select {
+		case sub.ch <- event:
+		default:
+			slow = append(slow, sub)
+		}
+	}
+	w.mu.Unlock()
+
+	for _, sub := range slow {
+		log.Printf("WARN: Dropping slow rule event subscriber (buffer %d full)", w.bufferSize)
+		w.remove(sub)
+	}
+}
+
+func (w *ruleWatcher) remove(sub *RuleEventSubscription) {
+	w.mu.Lock()
+	delete(w.subscribers, sub)
+	w.mu.Unlock()
+	sub.once.Do(func() { close(sub.ch) })
+}
+
+// parseRuleConfigMap parses the RuleSet YAML under key (normally 'rule.yaml') of a rule ConfigMap
+func parseRuleConfigMap(cm *corev1.ConfigMap, key string) (*models.RuleSet, error) {
+	yamlContent, ok := cm.Data[key]
+	if !ok {
+		return nil, fmt.Errorf("ConfigMap %s does not contain '%s' key", cm.Name, key)
+	}
+	var ruleSet models.RuleSet
+	if err := yaml.Unmarshal([]byte(yamlContent), &ruleSet); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal rule YAML: %w", err)
+	}
+	return &ruleSet, nil
+}
+
+// configMapAuthor returns the manager of the most recent managedFields entry and the
+// author to report: the admin user recorded by this server when it made the last
+// write, otherwise the manager itself
+func configMapAuthor(cm *corev1.ConfigMap) (string, string) {
+	var latest *metav1.ManagedFieldsEntry
+	for i := range cm.ManagedFields {
+		entry := &cm.ManagedFields[i]
+		if entry.Time == nil {
+			continue
+		}
+		if latest == nil || !entry.Time.Before(latest.Time) {
+			latest = entry
+		}
+	}
+	if latest == nil {
+		return "", ""
+	}
+	if latest.Manager == ruleFieldManager && cm.Annotations[annotationUpdatedBy] != "" {
+		return latest.Manager, cm.Annotations[annotationUpdatedBy]
+	}
+	return latest.Manager, latest.Manager
+}
+
+// cloneRuleSet copies a RuleSet so callers cannot modify the cache
+func cloneRuleSet(ruleSet *models.RuleSet) *models.RuleSet {
+	clone := *ruleSet
+	clone.Rules = make([]models.Rule, len(ruleSet.Rules))
+	for i, rule := range ruleSet.Rules {
+		clone.Rules[i] = rule
+		clone.Rules[i].Conditions = cloneConditions(rule.Conditions)
+		clone.Rules[i].Tags = append([]string(nil), rule.Tags...)
+		clone.Rules[i].Tests = cloneTests(rule.Tests)
+		if rule.Params != nil {
+			clone.Rules[i].Params = make(models.RuleParams, len(rule.Params))
+			for name, value := range rule.Params {
+				clone.Rules[i].Params[name] = copyValue(value)
+			}
+		}
+		if rule.Enabled != nil {
+			enabled := *rule.Enabled
+			clone.Rules[i].Enabled = &enabled
+		}
+	}
+	return &clone
+}
+
+func cloneConditions(conditions []models.Condition) []models.Condition {
+	if conditions == nil {
+		return nil
+	}
+	clone := make([]models.Condition, len(conditions))
+	for i, cond := range conditions {
+		clone[i] = cond
+		clone[i].Value = copyValue(cond.Value)
+		clone[i].All = cloneConditions(cond.All)
+		clone[i].Any = cloneConditions(cond.Any)
+		if cond.Not != nil {
+			not := cloneConditions([]models.Condition{*cond.Not})[0]
+			clone[i].Not = &not
+		}
+	}
+	return clone
+}
+
+func cloneTests(tests []models.RuleTest) []models.RuleTest {
+	if tests == nil {
+		return nil
+	}
+	clone := make([]models.RuleTest, len(tests))
+	for i, test := range tests {
+		clone[i] = test
+		if test.Event != nil {
+			clone[i].Event = copyValue(test.Event).(map[string]interface{})
+		}
+	}
+	return clone
+}
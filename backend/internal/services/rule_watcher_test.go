@@ -0,0 +1,36 @@
+package services
+
+import (
+	"admin_server/backend/internal/models"
+	"reflect"
+	"testing"
+)
+
+func TestCloneRuleSetCopiesNestedValues(t *testing.T) {
+	build := func() *models.RuleSet {
+		return &models.RuleSet{RulesetVersion: "1.0.0", Rules: []models.Rule{{
+			RuleID: "R1",
+			Conditions: []models.Condition{
+				{Field: "comm", Operator: OpIn, Value: []interface{}{"sh", "bash"}},
+				{Any: []models.Condition{{Field: "args.0", Operator: OpIn, Value: []interface{}{[]interface{}{"nested"}}}}},
+				{Not: &models.Condition{Field: "path", Operator: OpIn, Value: []interface{}{"/etc"}}},
+			},
+			Params: models.RuleParams{"binaries": []interface{}{"nc"}},
+			Tests:  []models.RuleTest{{Event: map[string]interface{}{"comm": "sh", "args": []interface{}{"-c"}}, Expect: models.RuleTestMatch}},
+		}}}
+	}
+	original, want := build(), build()
+
+	clone := cloneRuleSet(original)
+	rule := &clone.Rules[0]
+	rule.Conditions[0].Value.([]interface{})[0] = "zsh"
+	rule.Conditions[1].Any[0].Value.([]interface{})[0].([]interface{})[0] = "changed"
+	rule.Conditions[2].Not.Value.([]interface{})[0] = "/root"
+	rule.Params["binaries"].([]interface{})[0] = "ncat"
+	rule.Tests[0].Event["comm"] = "bash"
+	rule.Tests[0].Event["args"].([]interface{})[0] = "-e"
+
+	if !reflect.DeepEqual(original, want) {
+		t.Fatalf("changing the clone changed the original:\n got %+v\nwant %+v", original.Rules[0], want.Rules[0])
+	}
+}
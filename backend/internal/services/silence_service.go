@@ -0,0 +1,237 @@
+package services
+
+import (
+	"admin_server/backend/internal/config"
+	"admin_server/backend/internal/models"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// ErrInvalidSilence is returned when a silence fails validation
+var ErrInvalidSilence = errors.New("invalid silence")
+
+// SilenceService manages alert silences (maintenance windows)
+type SilenceService struct {
+	cfg   *config.Config
+	store silenceStore
+}
+
+// NewSilenceService creates a SilenceService using the same backend as the alert store
+func NewSilenceService(cfg *config.Config, redisClient *redis.Client) *SilenceService {
+	var store silenceStore
+	if cfg.AlertStore == "memory" {
+		store = newMemorySilenceStore()
+	} else {
+		store = newRedisSilenceStore(redisClient, cfg.SilenceRedisKey)
+	}
+
+	return &SilenceService{
+		cfg:   cfg,
+		store: store,
+	}
+}
+
+// GetSilences lists silences ordered by start time; expired ones are omitted unless includeExpired
+func (s *SilenceService) GetSilences(includeExpired bool) (*models.SilencesResponse, error) {
+	silences, err := s.store.List(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	result := make([]models.Silence, 0, len(silences))
+	for _, silence := range silences {
+		if !includeExpired && silenceExpired(silence, now) {
+			continue
+		}
+		result = append(result, silence)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].StartsAt < result[j].StartsAt
+	})
+
+	return &models.SilencesResponse{Silences: result}, nil
+}
+
+// GetSilence returns a single silence by ID
+func (s *SilenceService) GetSilence(id string) (*models.Silence, error) {
+	return s.store.Get(context.Background(), id)
+}
+
+// CreateSilence validates and stores a new silence
+func (s *SilenceService) CreateSilence(silence *models.Silence) (*models.Silence, error) {
+	if err := validateSilence(silence); err != nil {
+		return nil, err
+	}
+
+	silence.SilenceID = uuid.NewString()
+	silence.CreatedAt = time.Now().UTC().Format(time.RFC3339)
+	if err := s.store.Put(context.Background(), *silence); err != nil {
+		return nil, err
+	}
+
+	log.Printf("Silence %s created by %s: %s", silence.SilenceID, silence.CreatedBy, silence.Reason)
+	return silence, nil
+}
+
+// UpdateSilence replaces an existing silence, keeping its ID and creator; the caller
+// is recorded in UpdatedBy
+func (s *SilenceService) UpdateSilence(id string, silence *models.Silence) (*models.Silence, error) {
+	existing, err := s.store.Get(context.Background(), id)
+	if err != nil {
+		return nil, err
+	}
+
+	// 서버가 관리하는 필드는 검증 전에 기존 값으로 채움 (본문의 created_by는 무시)
+	silence.SilenceID = existing.SilenceID
+	silence.CreatedBy = existing.CreatedBy
+	silence.CreatedAt = existing.CreatedAt
+	silence.UpdatedAt = time.Now().UTC().Format(time.RFC3339)
+	if err := validateSilence(silence); err != nil {
+		return nil, err
+	}
+	if err := s.store.Put(context.Background(), *silence); err != nil {
+		return nil, err
+	}
+
+	log.Printf("Silence %s updated by %s", id, silence.UpdatedBy)
+	return silence, nil
+}
+
+// DeleteSilence removes a silence
+func (s *SilenceService) DeleteSilence(id string) error {
+	log.Printf("Deleting silence %s", id)
+	return s.store.Delete(context.Background(), id)
+}
+
+// Match returns the first silence active at ts whose matchers all match alert, or nil
+func (s *SilenceService) Match(alert models.Alert, ts time.Time) (*models.Silence, error) {
+	silences, err := s.store.List(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	for _, silence := range silences {
+		if silenceActive(silence, ts) && silenceMatches(silence, alert) {
+			return &silence, nil
+		}
+	}
+	return nil, nil
+}
+
+// CleanupExpired deletes silences whose end time has passed
+func (s *SilenceService) CleanupExpired() error {
+	ctx := context.Background()
+	silences, err := s.store.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	var expired []string
+	for _, silence := range silences {
+		if silenceExpired(silence, now) {
+			expired = append(expired, silence.SilenceID)
+		}
+	}
+	if len(expired) == 0 {
+		return nil
+	}
+
+	log.Printf("Removing %d expired silence(s)", len(expired))
+	return s.store.Delete(ctx, expired...)
+}
+
+// RunCleanup removes expired silences every SILENCE_CLEANUP_INTERVAL until ctx is cancelled
+func (s *SilenceService) RunCleanup(ctx context.Context) {
+	if s.cfg.SilenceCleanupInterval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(s.cfg.SilenceCleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.CleanupExpired(); err != nil {
+				log.Printf("WARN: Failed to clean up expired silences: %v", err)
+			}
+		}
+	}
+}
+
+func validateSilence(silence *models.Silence) error {
+	if silence.RuleID == "" && silence.Namespace == "" && silence.PodName == "" && silence.Severity == "" {
+		return fmt.Errorf("%w: at least one matcher (rule_id, namespace, pod_name, severity) is required", ErrInvalidSilence)
+	}
+	for _, pattern := range []string{silence.RuleID, silence.Namespace, silence.PodName, silence.Severity} {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("%w: bad matcher pattern %q", ErrInvalidSilence, pattern)
+		}
+	}
+	if silence.CreatedBy == "" {
+		return fmt.Errorf("%w: created_by is required", ErrInvalidSilence)
+	}
+	if silence.Reason == "" {
+		return fmt.Errorf("%w: reason is required", ErrInvalidSilence)
+	}
+
+	startsAt, err := time.Parse(time.RFC3339, silence.StartsAt)
+	if err != nil {
+		return fmt.Errorf("%w: starts_at must be RFC3339", ErrInvalidSilence)
+	}
+	endsAt, err := time.Parse(time.RFC3339, silence.EndsAt)
+	if err != nil {
+		return fmt.Errorf("%w: ends_at must be RFC3339", ErrInvalidSilence)
+	}
+	if !endsAt.After(startsAt) {
+		return fmt.Errorf("%w: ends_at must be after starts_at", ErrInvalidSilence)
+	}
+	return nil
+}
+
+func silenceActive(silence models.Silence, ts time.Time) bool {
+	startsAt, err := time.Parse(time.RFC3339, silence.StartsAt)
+	if err != nil {
+		return false
+	}
+	endsAt, err := time.Parse(time.RFC3339, silence.EndsAt)
+	if err != nil {
+		return false
+	}
+	return !ts.Before(startsAt) && ts.Before(endsAt)
+}
+
+func silenceExpired(silence models.Silence, now time.Time) bool {
+	endsAt, err := time.Parse(time.RFC3339, silence.EndsAt)
+	return err == nil && !now.Before(endsAt)
+}
+
+// silenceMatches reports whether every non-empty matcher matches the alert
+func silenceMatches(silence models.Silence, alert models.Alert) bool {
+	matchers := []struct{ pattern, value string }{
+		{silence.RuleID, alert.RuleID},
+		{silence.Namespace, alert.Namespace},
+		{silence.PodName, alert.PodName},
+		{strings.ToLower(silence.Severity), strings.ToLower(alert.Severity)},
+	}
+	for _, m := range matchers {
+		if m.pattern == "" {
+			continue
+		}
+		if ok, _ := path.Match(m.pattern, m.value); !ok {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,77 @@
+package services
+
+import (
+	"admin_server/backend/internal/config"
+	"admin_server/backend/internal/models"
+	"testing"
+	"time"
+)
+
+func TestUpdateSilenceKeepsCreator(t *testing.T) {
+	service := NewSilenceService(&config.Config{AlertStore: "memory"}, nil)
+	now := time.Now().UTC()
+	created, err := service.CreateSilence(&models.Silence{
+		RuleID:    "R1",
+		StartsAt:  now.Format(time.RFC3339),
+		EndsAt:    now.Add(time.Hour).Format(time.RFC3339),
+		CreatedBy: "alice",
+		Reason:    "maintenance",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	updated, err := service.UpdateSilence(created.SilenceID, &models.Silence{
+		RuleID:    "R1",
+		StartsAt:  created.StartsAt,
+		EndsAt:    now.Add(2 * time.Hour).Format(time.RFC3339),
+		CreatedBy: "mallory",
+		UpdatedBy: "bob",
+		Reason:    "maintenance extended",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	stored, err := service.GetSilence(created.SilenceID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, silence := range []*models.Silence{updated, stored} {
+		if silence.CreatedBy != "alice" || silence.CreatedAt != created.CreatedAt {
+			t.Fatalf("creator = %q at %q, want alice at %q", silence.CreatedBy, silence.CreatedAt, created.CreatedAt)
+		}
+		if silence.UpdatedBy != "bob" || silence.UpdatedAt == "" {
+			t.Fatalf("updater = %q at %q, want bob", silence.UpdatedBy, silence.UpdatedAt)
+		}
+	}
+}
+
+func TestUpdateSilenceWithoutCreatedBy(t *testing.T) {
+	service := NewSilenceService(&config.Config{AlertStore: "memory"}, nil)
+	now := time.Now().UTC()
+	created, err := service.CreateSilence(&models.Silence{
+		RuleID:    "R1",
+		StartsAt:  now.Format(time.RFC3339),
+		EndsAt:    now.Add(time.Hour).Format(time.RFC3339),
+		CreatedBy: "alice",
+		Reason:    "maintenance",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 인증된 PUT 본문에는 created_by가 없음: 핸들러는 updated_by만 채움
+	updated, err := service.UpdateSilence(created.SilenceID, &models.Silence{
+		RuleID:    "R1",
+		StartsAt:  created.StartsAt,
+		EndsAt:    now.Add(2 * time.Hour).Format(time.RFC3339),
+		UpdatedBy: "bob",
+		Reason:    "maintenance extended",
+	})
+	if err != nil {
+		t.Fatalf("UpdateSilence without created_by: %v", err)
+	}
+	if updated.CreatedBy != "alice" || updated.UpdatedBy != "bob" {
+		t.Fatalf("creator = %q, updater = %q, want alice and bob", updated.CreatedBy, updated.UpdatedBy)
+	}
+}
@@ -0,0 +1,146 @@
+package services
+
+import (
+	"admin_server/backend/internal/models"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ErrSilenceNotFound is returned when no silence has the requested ID
+var ErrSilenceNotFound = errors.New("silence not found")
+
+// silenceStore persists silences keyed by ID
+type silenceStore interface {
+	List(ctx context.Context) ([]models.Silence, error)
+	Get(ctx context.Context, id string) (*models.Silence, error)
+	Put(ctx context.Context, silence models.Silence) error
+	Delete(ctx context.Context, ids ...string) error
+}
+
+// redisSilenceStore keeps silences as JSON in a single hash
+type redisSilenceStore struct {
+	client *redis.Client
+	key    string
+}
+
+func newRedisSilenceStore(client *redis.Client, key string) *redisSilenceStore {
+	return &redisSilenceStore{client: client, key: key}
+}
+
+func (r *redisSilenceStore) List(ctx context.Context) ([]models.Silence, error) {
+	values, err := r.client.HGetAll(ctx, r.key).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read silences from Redis: %w", err)
+	}
+	silences := make([]models.Silence, 0, len(values))
+	for id, raw := range values {
+		var silence models.Silence
+		if err := json.Unmarshal([]byte(raw), &silence); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal silence %s: %w", id, err)
+		}
+		silences = append(silences, silence)
+	}
+	return silences, nil
+}
+
+func (r *redisSilenceStore) Get(ctx context.Context, id string) (*models.Silence, error) {
+	raw, err := r.client.HGet(ctx, r.key, id).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrSilenceNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read silence from Redis: %w", err)
+	}
+	var silence models.Silence
+	if err := json.Unmarshal([]byte(raw), &silence); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal silence %s: %w", id, err)
+	}
+	return &silence, nil
+}
+
+func (r *redisSilenceStore) Put(ctx context.Context, silence models.Silence) error {
+	silenceJSON, err := json.Marshal(silence)
+	if err != nil {
+		return fmt.Errorf("failed to marshal silence: %w", err)
+	}
+	if err := r.client.HSet(ctx, r.key, silence.SilenceID, silenceJSON).Err(); err != nil {
+		return fmt.Errorf("failed to store silence in Redis: %w", err)
+	}
+	return nil
+}
+
+func (r *redisSilenceStore) Delete(ctx context.Context, ids ...string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	deleted, err := r.client.HDel(ctx, r.key, ids...).Result()
+	if err != nil {
+		return fmt.Errorf("failed to delete silence from Redis: %w", err)
+	}
+	if deleted == 0 {
+		return ErrSilenceNotFound
+	}
+	return nil
+}
+
+// memorySilenceStore keeps silences in process memory (local development only)
+type memorySilenceStore struct {
+	mu       sync.RWMutex
+	silences map[string]models.Silence
+}
+
+func newMemorySilenceStore() *memorySilenceStore {
+	return &memorySilenceStore{silences: make(map[string]models.Silence)}
+}
+
+func (m *memorySilenceStore) List(ctx context.Context) ([]models.Silence, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	silences := make([]models.Silence, 0, len(m.silences))
+	for _, silence := range m.silences {
+		silences = append(silences, silence)
+	}
+	return silences, nil
+}
+
+func (m *memorySilenceStore) Get(ctx context.Context, id string) (*models.Silence, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	silence, ok := m.silences[id]
+	if !ok {
+		return nil, ErrSilenceNotFound
+	}
+	return &silence, nil
+}
+
+func (m *memorySilenceStore) Put(ctx context.Context, silence models.Silence) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.silences[silence.SilenceID] = silence
+	return nil
+}
+
+func (m *memorySilenceStore) Delete(ctx context.Context, ids ...string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	found := false
+	for _, id := range ids {
+		if _, ok := m.silences[id]; ok {
+			delete(m.silences, id)
+			found = true
+		}
+	}
+	if len(ids) > 0 && !found {
+		return ErrSilenceNotFound
+	}
+	return nil
+}
@@ -26,6 +26,20 @@ func NewSyscallService(cfg *config.Config, ccslClient *redis.Client) *SyscallSer
 	}
 }
 
+// CallableSyscallSet returns the members of the cluster_callable_syscalls set for lookups
+func (s *SyscallService) CallableSyscallSet(ctx context.Context) (map[string]struct{}, error) {
+	syscalls, err := s.ccslClient.SMembers(ctx, SyscallSetKey).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve syscalls from Redis: %w", err)
+	}
+
+	set := make(map[string]struct{}, len(syscalls))
+	for _, name := range syscalls {
+		set[name] = struct{}{}
+	}
+	return set, nil
+}
+
 // GetCallableSyscalls retrieves all syscalls that the cluster can call
 func (s *SyscallService) GetCallableSyscalls() (*models.CallableSyscallsResponse, error) {
 	log.Println("Getting callable syscalls from CCSL Redis") // 로그 수정
@@ -4,8 +4,13 @@ import (
 	"log"
 	"os"
 
+	"admin_server/backend/internal/auth"
+	"admin_server/backend/internal/cli"
 	"admin_server/backend/internal/config"
+	"admin_server/backend/internal/consumer"
 	"admin_server/backend/internal/handlers"
+	"admin_server/backend/internal/notifier"
+	"admin_server/backend/internal/reloader"
 	"admin_server/backend/internal/services"
 
 	"context" // 컨텍스트 import
@@ -17,6 +22,11 @@ import (
 )
 
 func main() {
+	// 서브커맨드: `admin-server test-rules FILE...` (서버를 띄우지 않고 룰 테스트만 실행)
+	if len(os.Args) > 1 && os.Args[1] == "test-rules" {
+		os.Exit(cli.TestRules(os.Args[2:], os.Stdout, os.Stderr))
+	}
+
 	// Load configuration
 	cfg := config.Load()
 
@@ -49,29 +59,70 @@ func main() {
 	log.Println("Successfully connected to CCSL Redis")
 
 	// --- 3. 서비스 초기화 ---
-	ruleService := services.NewRuleService(cfg, clientset)
 	// [수정] SyscallService에 Redis 클라이언트 주입
 	syscallService := services.NewSyscallService(cfg, ccslRedisClient)
-	alertService := services.NewAlertService(cfg)
+	// 룰 변경 후 룰 엔진 리로드 알림 (RULE_RELOAD_NOTIFIERS가 비어 있으면 알림 없음)
+	ruleReloader, err := reloader.NewManager(cfg, clientset, ccslRedisClient)
+	if err != nil {
+		log.Fatalf("Failed to configure rule reload notifiers: %v", err)
+	}
+	// 룰 템플릿 (RULE_TEMPLATE_CONFIG_MAP_NAME, 룰을 쓸 때 조건으로 전개됨)
+	ruleTemplateService := services.NewRuleTemplateService(cfg, clientset)
+	// 룰셋 레지스트리: 기본 룰셋(NAMESPACE/CONFIG_MAP_NAME)과 RULESETS에 등록한 룰셋
+	ruleService, err := services.NewRuleService(cfg, clientset, ccslRedisClient, syscallService, ruleTemplateService, ruleReloader)
+	if err != nil {
+		log.Fatalf("Failed to configure rulesets: %v", err)
+	}
+	ruleService.StartWatch(ctx) // 룰셋별 ConfigMap informer (GetRules 캐시 및 변경 이벤트)
+	// 알림 전송 디스패처 (설정된 sink가 없으면 아무것도 보내지 않음)
+	alertNotifier := notifier.NewDispatcher(cfg)
+	alertNotifier.Start(ctx)
+	incidentService := services.NewIncidentService(cfg, ccslRedisClient)
+	silenceService := services.NewSilenceService(cfg, ccslRedisClient)
+	go silenceService.RunCleanup(ctx) // 만료된 사일런스 주기적 정리
+	alertService := services.NewAlertService(cfg, ccslRedisClient, alertNotifier, incidentService, silenceService)
 	testService := services.NewTestService(cfg)
 
 	// [삭제] 중복되었던 서비스 초기화 블록 삭제
 
+	// Kafka 알림 컨슈머 (KAFKA_ENABLED=true일 때만 실행, 웹훅과 동일한 경로로 저장)
+	if cfg.KafkaEnabled {
+		alertConsumer := consumer.NewAlertConsumer(cfg, alertService)
+		defer alertConsumer.Close()
+		go alertConsumer.Serve(ctx) // 오류로 멈추면 백오프 후 다시 시작
+		log.Printf("Consuming alerts from Kafka topic %s", cfg.KafkaAlertTopic)
+	}
+
 	// --- 4. 핸들러 초기화 ---
-	ruleHandler := handlers.NewRuleHandler(ruleService)
+	ruleHandler := handlers.NewRuleHandler(ruleService, alertService)
+	ruleTemplateHandler := handlers.NewRuleTemplateHandler(ruleTemplateService, ruleService)
 	syscallHandler := handlers.NewSyscallHandler(syscallService)
 	alertHandler := handlers.NewAlertHandler(alertService)
+	incidentHandler := handlers.NewIncidentHandler(incidentService, alertService)
+	silenceHandler := handlers.NewSilenceHandler(silenceService)
 	testHandler := handlers.NewTestHandler(testService)
 
+	// --- 5. 인증 설정 ---
+	authenticator, err := auth.NewFromConfig(cfg)
+	if err != nil {
+		log.Fatalf("Failed to configure authentication: %v", err)
+	}
+	if authenticator == nil {
+		log.Println("WARN: AUTH_ENABLED=false, /api/v1 is open to anyone who can reach the service")
+	}
+
 	// Setup router
-	router := gin.Default()
+	// access_token 쿼리는 로거보다 먼저 헤더로 옮겨서 접근 로그에 토큰이 남지 않도록 함
+	router := gin.New()
+	router.Use(auth.QueryToken(), gin.Logger(), gin.Recovery())
 
 	// CORS middleware
 	router.Use(func(c *gin.Context) {
 		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
 		c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With")
-		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, DELETE")
+		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With, If-Match, Last-Event-ID")
+		c.Writer.Header().Set("Access-Control-Expose-Headers", "ETag")
+		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, PATCH, DELETE")
 
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(204)
@@ -81,22 +132,90 @@ func main() {
 		c.Next()
 	})
 
+	// 웹훅은 Bearer 토큰 대신 룰 엔진과 공유한 시크릿/HMAC 서명으로 보호 (설정이 없으면 인증 비활성화 시에만 열림)
+	webhookAuth := auth.WebhookMiddleware(cfg.WebhookToken, cfg.WebhookHMACSecret, !cfg.AuthEnabled)
+	router.POST("/api/v1/alerts/webhook", webhookAuth, alertHandler.ReceiveWebhook)
+	// 룰 엔드포인트는 기본 룰셋(/rules)과 RULESETS에 등록한 룰셋(/rulesets/:name/rules)에 같은 핸들러로 등록
+	ruleScopes := []string{"/rules", "/rulesets/:name/rules"}
+	for _, rules := range ruleScopes {
+		router.POST("/api/v1"+rules+"/engines/report", webhookAuth, ruleHandler.ReportEngineStatus)
+	}
+
 	// API routes
 	api := router.Group("/api/v1")
+	if authenticator != nil {
+		api.Use(auth.Middleware(authenticator))
+	}
+
+	// viewer: read-only access
+	viewer := api.Group("", auth.RequireRole(auth.RoleViewer))
+	{
+		viewer.GET("/rulesets", ruleHandler.ListRulesets)
+		viewer.GET("/rulesets/:name", ruleHandler.GetRuleset)
+		for _, rules := range ruleScopes {
+			viewer.GET(rules, ruleHandler.GetRules)
+			viewer.GET(rules+"/status", ruleHandler.GetRuleStatus)
+			viewer.GET(rules+"/candidate", ruleHandler.GetCandidateRules)
+			viewer.GET(rules+"/engines", ruleHandler.GetEngineStatuses)
+			viewer.GET(rules+"/events", ruleHandler.StreamRuleEvents)
+			viewer.GET(rules+"/history", ruleHandler.GetRuleHistory)
+			viewer.GET(rules+"/history/:rev", ruleHandler.GetRuleRevision)
+			viewer.GET(rules+"/history/:rev/diff", ruleHandler.DiffRuleRevision)
+			viewer.GET(rules+"/export", ruleHandler.ExportRules)
+			viewer.POST(rules+"/validate", ruleHandler.ValidateRules) // dry run, writes nothing
+			viewer.POST(rules+"/simulate", ruleHandler.SimulateRules) // dry run against recorded events
+			viewer.POST(rules+"/test", ruleHandler.TestRules)         // embedded rule tests
+			viewer.GET(rules+"/:rule_id", ruleHandler.GetRule)
+		}
+		viewer.GET("/rule-templates", ruleTemplateHandler.GetRuleTemplates)
+		viewer.GET("/rule-templates/:template_id", ruleTemplateHandler.GetRuleTemplate)
+
+		viewer.GET("/syscalls/callable", syscallHandler.GetCallableSyscalls)
+
+		viewer.GET("/alerts", alertHandler.GetAlerts)
+		viewer.GET("/alerts/stream", alertHandler.StreamAlerts)
+		viewer.GET("/alerts/:id", alertHandler.GetAlert)
+
+		viewer.GET("/incidents", incidentHandler.GetIncidents)
+		viewer.GET("/incidents/:id", incidentHandler.GetIncident)
+		viewer.GET("/incidents/:id/alerts", incidentHandler.GetIncidentAlerts)
+
+		viewer.GET("/silences", silenceHandler.GetSilences)
+		viewer.GET("/silences/:id", silenceHandler.GetSilence)
+	}
+
+	// operator: alert triage, silences and test attacks
+	operator := api.Group("", auth.RequireRole(auth.RoleOperator))
 	{
-		// Rules endpoints
-		api.GET("/rules", ruleHandler.GetRules)
-		api.PUT("/rules", ruleHandler.UpdateRules)
+		operator.PATCH("/alerts/:id", alertHandler.UpdateAlert)
+		operator.POST("/alerts/:id/comments", alertHandler.AddAlertComment)
 
-		// Syscalls endpoints
-		api.GET("/syscalls/callable", syscallHandler.GetCallableSyscalls)
+		operator.POST("/silences", silenceHandler.CreateSilence)
+		operator.PUT("/silences/:id", silenceHandler.UpdateSilence)
+		operator.DELETE("/silences/:id", silenceHandler.DeleteSilence)
 
-		// Alerts endpoints
-		api.GET("/alerts", alertHandler.GetAlerts)
-		api.POST("/alerts/webhook", alertHandler.ReceiveWebhook)
+		operator.POST("/tests/trigger", testHandler.TriggerTest)
+	}
+
+	// rule-admin: changes to the production rule ConfigMap
+	ruleAdmin := api.Group("", auth.RequireRole(auth.RoleRuleAdmin))
+	{
+		for _, rules := range ruleScopes {
+			ruleAdmin.PUT(rules, ruleHandler.UpdateRules)
+			ruleAdmin.POST(rules+"/rollback/:rev", ruleHandler.RollbackRules)
+			ruleAdmin.PUT(rules+"/candidate", ruleHandler.StageRules)
+			ruleAdmin.POST(rules+"/promote", ruleHandler.PromoteCandidate)
+			ruleAdmin.PATCH(rules, ruleHandler.PatchRules)
+			ruleAdmin.POST(rules+"/import", ruleHandler.ImportRules)
+			// 개별 룰 편집 (status, candidate, history 등 고정 경로와 같은 rule_id는 사용할 수 없음)
+			ruleAdmin.POST(rules+"/:rule_id", ruleHandler.CreateRule)
+			ruleAdmin.PUT(rules+"/:rule_id", ruleHandler.ReplaceRule)
+			ruleAdmin.DELETE(rules+"/:rule_id", ruleHandler.DeleteRule)
+		}
 
-		// Test endpoints
-		api.POST("/tests/trigger", testHandler.TriggerTest)
+		ruleAdmin.POST("/rule-templates/:template_id", ruleTemplateHandler.CreateRuleTemplate)
+		ruleAdmin.PUT("/rule-templates/:template_id", ruleTemplateHandler.ReplaceRuleTemplate)
+		ruleAdmin.DELETE("/rule-templates/:template_id", ruleTemplateHandler.DeleteRuleTemplate)
 	}
 
 	// Health check
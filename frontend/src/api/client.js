@@ -13,6 +13,11 @@ const client = axios.create({
 client.interceptors.request.use(
   (config) => {
     console.log(`[API] ${config.method.toUpperCase()} ${config.url}`)
+    // API 토큰 (AUTH_API_TOKENS 또는 OIDC access token)
+    const token = localStorage.getItem('apiToken')
+    if (token) {
+      config.headers.Authorization = `Bearer ${token}`
+    }
     return config
   },
   (error) => {
@@ -1,12 +1,24 @@
 import client from './client'
 
+// GET /rules가 돌려준 ConfigMap resourceVersion (PUT 시 If-Match로 전송)
+let rulesETag = null
+
 export const getRules = async () => {
   const response = await client.get('/rules')
+  rulesETag = response.headers.etag || null
   return response.data
 }
 
 export const updateRules = async (ruleSet) => {
-  const response = await client.put('/rules', ruleSet)
+  const headers = rulesETag ? { 'If-Match': rulesETag } : {}
+  const response = await client.put('/rules', ruleSet, { headers })
+  rulesETag = response.headers.etag || rulesETag
+  return response.data
+}
+
+// 저장하지 않고 검증만 수행 (problems/warnings 반환)
+export const validateRules = async (ruleSet) => {
+  const response = await client.post('/rules/validate', ruleSet)
   return response.data
 }
 
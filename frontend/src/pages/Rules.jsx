@@ -2,6 +2,34 @@ import React, { useState, useEffect } from 'react'
 import { getRules } from '../api/rules'
 import './Rules.css'
 
+// 조건 하나 또는 any/all/not 그룹을 중첩해서 표시
+function ConditionView({ condition, formatValue }) {
+  if (condition.not) {
+    return (
+      <div className="condition condition-group">
+        <strong>NOT</strong>
+        <ConditionView condition={condition.not} formatValue={formatValue} />
+      </div>
+    )
+  }
+  const group = condition.all ? 'all' : condition.any ? 'any' : null
+  if (group) {
+    return (
+      <div className="condition condition-group">
+        <strong>{group === 'all' ? 'ALL (AND)' : 'ANY (OR)'}</strong>
+        {condition[group].map((child, index) => (
+          <ConditionView key={index} condition={child} formatValue={formatValue} />
+        ))}
+      </div>
+    )
+  }
+  return (
+    <div className="condition">
+      <strong>{condition.field}</strong> {condition.operator} {formatValue(condition.value)}
+    </div>
+  )
+}
+
 function Rules() {
   const [rules, setRules] = useState(null)
   const [loading, setLoading] = useState(true)
@@ -60,14 +88,21 @@ function Rules() {
 
       {rules.rules.map((rule, index) => (
         <div key={index} className="card rule-item">
-          <div className="rule-id">{rule.rule_id}</div>
+          <div className="rule-id">
+            {rule.rule_id}
+            {rule.enabled === false && <span className="rule-disabled"> (비활성)</span>}
+          </div>
           <div className="rule-description">{rule.description}</div>
+          <div className="rule-meta">
+            <span><strong>심각도:</strong> {rule.severity || '-'}</span>
+            <span><strong>조치:</strong> {rule.action || 'alert'}</span>
+            {rule.owner && <span><strong>담당:</strong> {rule.owner}</span>}
+            {rule.tags && rule.tags.length > 0 && <span><strong>태그:</strong> {rule.tags.join(', ')}</span>}
+          </div>
           <div style={{ marginTop: '15px' }}>
             <strong>조건:</strong>
             {rule.conditions.map((condition, condIndex) => (
-              <div key={condIndex} className="condition">
-                <strong>{condition.field}</strong> {condition.operator} {formatValue(condition.value)}
-              </div>
+              <ConditionView key={condIndex} condition={condition} formatValue={formatValue} />
             ))}
           </div>
         </div>
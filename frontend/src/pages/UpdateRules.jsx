@@ -1,8 +1,11 @@
 import React, { useState, useEffect } from 'react'
-import { getRules, updateRules } from '../api/rules'
+import { getRules, updateRules, validateRules } from '../api/rules'
 import Editor from '@monaco-editor/react'
 import './UpdateRules.css'
 
+// 검증 문제/경고를 "pointer: message" 목록으로 표시
+const formatProblems = (problems) => problems.map((p) => `${p.pointer}: ${p.message}`).join('; ')
+
 function UpdateRules() {
   const [rules, setRules] = useState(null)
   const [yamlContent, setYamlContent] = useState('')
@@ -53,11 +56,34 @@ function UpdateRules() {
         return
       }
 
+      // 저장 전에 서버 검증: 문제가 있으면 저장하지 않고, 경고(알 수 없는 syscall 등)는 확인 후 저장
+      const validation = await validateRules(parsedRules)
+      if (!validation.valid) {
+        setError('룰 검증에 실패했습니다: ' + formatProblems(validation.problems))
+        return
+      }
+      if (
+        validation.warnings.length &&
+        !window.confirm(`검증 경고가 있습니다:\n${formatProblems(validation.warnings)}\n\n그래도 저장하시겠습니까?`)
+      ) {
+        return
+      }
+
       const response = await updateRules(parsedRules)
-      setSuccess(`룰이 성공적으로 업데이트되었습니다! 새 버전: ${response.new_version || '미정'}`)
+      const warnings = (response.warnings || []).map((w) => w.message)
+      setSuccess(
+        `룰이 성공적으로 업데이트되었습니다! 새 버전: ${response.new_version || '미정'}` +
+          (warnings.length ? ` (경고: ${warnings.join('; ')})` : '')
+      )
       setRules(parsedRules)
     } catch (err) {
-      setError(err.response?.data?.error || err.message || '룰 업데이트에 실패했습니다.')
+      if (err.response?.status === 409) {
+        setError('다른 사용자가 먼저 룰을 변경했습니다. 새로고침 후 변경 사항을 확인하고 다시 시도하세요.')
+      } else if (err.response?.data?.problems?.length) {
+        setError('룰 검증에 실패했습니다: ' + formatProblems(err.response.data.problems))
+      } else {
+        setError(err.response?.data?.error || err.message || '룰 업데이트에 실패했습니다.')
+      }
     } finally {
       setSaving(false)
     }